@@ -30,6 +30,31 @@ const (
 	ConditionReasonNotReady nddv1.ConditionReason = "NotReady"
 )
 
+// ConditionReasons a template is not ready.
+const (
+	ConditionReasonInvalidTemplate   nddv1.ConditionReason = "InvalidTemplate"
+	ConditionReasonReferenceNotFound nddv1.ConditionReason = "ReferenceNotFound"
+	ConditionReasonInvalidFabric     nddv1.ConditionReason = "InvalidFabric"
+)
+
+// ConditionReasons a topology is not healthy.
+const (
+	ConditionReasonDegraded nddv1.ConditionReason = "Degraded"
+)
+
+// ConditionKindHealthy reports the health of a topology resource, e.g. a link which
+// endpoints do not exist. The Ready condition of the resources reconciled by the managed
+// reconciler reflects the reconciliation and is set to available when it succeeds.
+const ConditionKindHealthy nddv1.ConditionKind = "Healthy"
+
+// ConditionReasons a resource is or is not healthy.
+const (
+	ConditionReasonHealthy           nddv1.ConditionReason = "Healthy"
+	ConditionReasonNodeNotFound      nddv1.ConditionReason = "NodeNotFound"
+	ConditionReasonInterfaceConflict nddv1.ConditionReason = "InterfaceConflict"
+	ConditionReasonTargetMismatch    nddv1.ConditionReason = "TargetMismatch"
+)
+
 // Ready indicates that the resource is ready.
 func Ready() nddv1.Condition {
 	return nddv1.Condition{
@@ -49,3 +74,23 @@ func NotReady() nddv1.Condition {
 		Reason:             ConditionReasonNotReady,
 	}
 }
+
+// Healthy indicates that the resource is healthy.
+func Healthy() nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonHealthy,
+	}
+}
+
+// Unhealthy indicates that the resource is not healthy for the reason.
+func Unhealthy(reason nddv1.ConditionReason) nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+	}
+}
@@ -120,6 +120,41 @@ func (x *Definition) GetNamespacedName() string {
 	return strings.Join([]string{x.Namespace, x.Name}, "/")
 }
 
+func (x *Definition) GetPlan() bool {
+	if x.Spec.Properties == nil {
+		return false
+	}
+	return x.Spec.Properties.Plan
+}
+
+func (x *Definition) GetVendorTypeInfo() []*NodeProperties {
+	if x.Spec.Properties == nil {
+		return nil
+	}
+	return x.Spec.Properties.VendorTypeInfo
+}
+
+func (x *Definition) GetDefaults() *TopologyDefaults {
+	if x.Spec.Properties == nil {
+		return nil
+	}
+	return x.Spec.Properties.Defaults
+}
+
+func (x *Definition) GetAllocations(templateName string) *FabricAllocations {
+	if x.Status.Allocations == nil {
+		return nil
+	}
+	return x.Status.Allocations[templateName]
+}
+
+func (x *Definition) SetAllocations(templateName string, a *FabricAllocations) {
+	if x.Status.Allocations == nil {
+		x.Status.Allocations = map[string]*FabricAllocations{}
+	}
+	x.Status.Allocations[templateName] = a
+}
+
 func (x *Definition) GetOrganization() string {
 
 	return odns.Name2OdnsTopoResource(x.GetName() + ".dummy").GetOrganization()
@@ -34,12 +34,136 @@ type DefinitionSpec struct {
 // A DefinitionStatus represents the observed state of a Definition.
 type DefinitionStatus struct {
 	nddv1.ResourceStatus `json:",inline"`
+	// Plan holds the changes the definition would apply, only set in plan mode
+	Plan *DefinitionPlan `json:"plan,omitempty"`
+	// Allocations holds the indexes allocated per template, they are reused when
+	// the fabric is rendered again such that the existing cabling stays stable
+	Allocations map[string]*FabricAllocations `json:"allocations,omitempty"`
+	// Bindings holds the discovered targets bound to the planned fabric nodes
+	Bindings []*NodeBinding `json:"bindings,omitempty"`
+	// DigitalTwinReference refers to the ConfigMap with the containerlab topology of the
+	// digital twin, only set when a template or discovery rule is rendered as digital twin
+	DigitalTwinReference *nddv1.Reference `json:"digitalTwinRef,omitempty"`
+	// CablingPlanReference refers to the ConfigMap with the cabling sheet and the bill
+	// of materials of the fabrics of the templates
+	CablingPlanReference *nddv1.Reference `json:"cablingPlanRef,omitempty"`
+}
+
+// NodeBinding binds a discovered target to a planned fabric node with the same
+// serial number or mac address
+type NodeBinding struct {
+	Node   string `json:"node"`
+	Target string `json:"target"`
+	// differences between the planned node and the discovered target
+	Mismatches []string `json:"mismatches,omitempty"`
+}
+
+// FabricAllocations holds the indexes allocated when a fabric was rendered
+type FabricAllocations struct {
+	Pods        []*PodAllocation        `json:"pods,omitempty"`
+	Links       []*LinkAllocation       `json:"links,omitempty"`
+	BorderLeafs []*BorderLeafAllocation `json:"borderLeafs,omitempty"`
+}
+
+// PodAllocation holds the first pod index allocated to a pod template
+type PodAllocation struct {
+	// key of the pod template, the template or definition reference with its
+	// occurrence, or the name or position of a native pod template
+	PodTemplate string `json:"podTemplate"`
+	PodIndex    uint32 `json:"podIndex"`
+}
+
+// LinkAllocation holds the interface indexes allocated to a link
+type LinkAllocation struct {
+	NodeA  string `json:"nodeA"`
+	IndexA uint32 `json:"indexA"`
+	NodeB  string `json:"nodeB"`
+	IndexB uint32 `json:"indexB"`
+	// uplink between the nodes, counting from 1
+	Uplink uint32 `json:"uplink"`
+}
+
+// BorderLeafAllocation holds the index of a borderleaf on the nodes it connects to
+type BorderLeafAllocation struct {
+	NodeName string `json:"nodeName"`
+	// pod of the spines the borderleaf connects to, 0 when it connects to the superspines
+	PodIndex uint32 `json:"podIndex,omitempty"`
+	Index    uint32 `json:"index"`
+}
+
+// DefinitionPlan holds the planned changes of the resources owned by the Definition
+type DefinitionPlan struct {
+	Nodes *DefinitionPlanChanges `json:"nodes,omitempty"`
+	Links *DefinitionPlanChanges `json:"links,omitempty"`
+	// Diff details how the nodes, links and interfaces of the fabric change
+	Diff *FabricDiff `json:"diff,omitempty"`
+}
+
+// DefinitionPlanChanges holds the names of the resources that would be
+// created, updated or deleted
+type DefinitionPlanChanges struct {
+	Create []string `json:"create,omitempty"`
+	Update []string `json:"update,omitempty"`
+	Delete []string `json:"delete,omitempty"`
+}
+
+// FabricDiff holds the differences between 2 renders of a fabric
+type FabricDiff struct {
+	Nodes *FabricDiffNodes `json:"nodes,omitempty"`
+	Links *FabricDiffLinks `json:"links,omitempty"`
+	// ReindexedInterfaces holds the interfaces of the moved links which changed
+	ReindexedInterfaces []*FabricDiffInterface `json:"reindexedInterfaces,omitempty"`
+}
+
+type FabricDiffNodes struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Relabeled holds the nodes of which the labels changed
+	Relabeled []*FabricDiffLabels `json:"relabeled,omitempty"`
+}
+
+// FabricDiffLabels holds the rendered labels of a node or link which changed,
+// labels added by others are not compared
+type FabricDiffLabels struct {
+	Name string `json:"name"`
+	// changed labels as key: old -> new
+	Changes []string `json:"changes"`
+}
+
+type FabricDiffLinks struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Moved holds the links between the same nodes which use other interfaces
+	Moved []*FabricDiffLink `json:"moved,omitempty"`
+	// Relabeled holds the links of which the labels changed
+	Relabeled []*FabricDiffLabels `json:"relabeled,omitempty"`
+}
+
+type FabricDiffLink struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type FabricDiffInterface struct {
+	Node string `json:"node"`
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 // DefinitionProperties define the properties of the Definition
 type DefinitionProperties struct {
 	Templates      []*DefinitionTemplate      `json:"templates,omitempty"`
 	DiscoveryRules []*DefinitionDiscoveryRule `json:"discoveryRules,omitempty"`
+	// Plan computes the changes of the definition and reports them in the status
+	// without applying them
+	// +kubebuilder:default=false
+	Plan bool `json:"plan,omitempty"`
+	// VendorTypeInfo overrides the platform per vendor type of the topology, by default
+	// the topology uses the platforms of the vendor types used in the templates
+	VendorTypeInfo []*NodeProperties `json:"vendorTypeInfo,omitempty"`
+	// Defaults are the defaults of the topology, the position of the nodes is left
+	// unset when it is not specified
+	Defaults *TopologyDefaults `json:"defaults,omitempty"`
 }
 
 type DefinitionTemplate struct {
@@ -66,6 +190,7 @@ type DefinitionRule struct {
 // +kubebuilder:printcolumn:name="DEP",type="string",JSONPath=".status.oda[?(@.key=='deployment')].value"
 // +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".status.oda[?(@.key=='availability-zone')].value"
 // +kubebuilder:printcolumn:name="TOPO",type="string",JSONPath=".status.topology-name"
+// +kubebuilder:printcolumn:name="PLAN",type="boolean",JSONPath=".spec.properties.plan"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:categories={yndd,topo}
 type Definition struct {
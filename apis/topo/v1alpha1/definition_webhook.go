@@ -0,0 +1,89 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (x *Definition) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(x).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-topo-yndd-io-v1alpha1-definition,mutating=false,failurePolicy=fail,sideEffects=None,groups=topo.yndd.io,resources=definitions,verbs=create;update,versions=v1alpha1,name=vdefinition.topo.yndd.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Definition{}
+
+// ValidateCreate implements webhook.Validator
+func (x *Definition) ValidateCreate() error {
+	return x.validate()
+}
+
+// ValidateUpdate implements webhook.Validator
+func (x *Definition) ValidateUpdate(old runtime.Object) error {
+	return x.validate()
+}
+
+// ValidateDelete implements webhook.Validator
+func (x *Definition) ValidateDelete() error {
+	return nil
+}
+
+func (x *Definition) validate() error {
+	fldPath := field.NewPath("spec", "properties")
+	allErrs := field.ErrorList{}
+
+	if x.Spec.Properties == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "a definition requires templates or discoveryRules"))
+		return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: DefinitionKind}, x.Name, allErrs)
+	}
+
+	templates := map[string]struct{}{}
+	for i, dt := range x.Spec.Properties.Templates {
+		if dt.NamespacedName == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("templates").Index(i).Child("namespacedName"), "a template reference requires a namespacedName"))
+			continue
+		}
+		if _, ok := templates[dt.NamespacedName]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("templates").Index(i).Child("namespacedName"), dt.NamespacedName))
+		}
+		templates[dt.NamespacedName] = struct{}{}
+	}
+	rules := map[string]struct{}{}
+	for i, dr := range x.Spec.Properties.DiscoveryRules {
+		if dr.NamespacedName == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("discoveryRules").Index(i).Child("namespacedName"), "a discovery rule reference requires a namespacedName"))
+			continue
+		}
+		if _, ok := rules[dr.NamespacedName]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("discoveryRules").Index(i).Child("namespacedName"), dr.NamespacedName))
+		}
+		rules[dr.NamespacedName] = struct{}{}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: DefinitionKind}, x.Name, allErrs)
+}
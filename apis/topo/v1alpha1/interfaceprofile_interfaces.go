@@ -0,0 +1,77 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultInterfaceNameFormat         = "int-1/%d"
+	DefaultBreakoutInterfaceNameFormat = "int-1/%d/%d"
+)
+
+func (x *InterfaceProfile) GetNamespacedName() string {
+	return strings.Join([]string{x.Namespace, x.Name}, "/")
+}
+
+func (x *InterfaceProfileProperties) GetInterfaceNameFormat() string {
+	if x.InterfaceNameFormat == "" {
+		return DefaultInterfaceNameFormat
+	}
+	return x.InterfaceNameFormat
+}
+
+func (x *InterfaceProfileProperties) GetBreakoutInterfaceNameFormat() string {
+	if x.BreakoutInterfaceNameFormat == "" {
+		return DefaultBreakoutInterfaceNameFormat
+	}
+	return x.BreakoutInterfaceNameFormat
+}
+
+// GetUplinkOffset returns the offset of the uplink index for a given position
+// an explicit position offset takes precedence over the first uplink port
+func (x *InterfaceProfileProperties) GetUplinkOffset(pos Position) uint32 {
+	for _, po := range x.PositionOffsets {
+		if po.Position == pos {
+			return po.Offset
+		}
+	}
+	if x.FirstUplinkPort > 0 {
+		return x.FirstUplinkPort - 1
+	}
+	return 0
+}
+
+// GetPort returns the physical port of the interface with index idx
+// idx counts from 1, with breakout the index is spread over the breakout
+// interfaces of the physical ports
+func (x *InterfaceProfileProperties) GetPort(idx uint32) uint32 {
+	if x.Breakout > 1 {
+		return ((idx - 1) / x.Breakout) + 1
+	}
+	return idx
+}
+
+// GetInterfaceName returns the name of the interface with index idx
+func (x *InterfaceProfileProperties) GetInterfaceName(idx uint32) string {
+	if x.Breakout > 1 {
+		return fmt.Sprintf(x.GetBreakoutInterfaceNameFormat(), x.GetPort(idx), ((idx-1)%x.Breakout)+1)
+	}
+	return fmt.Sprintf(x.GetInterfaceNameFormat(), x.GetPort(idx))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// InterfaceProfileSpec struct
+type InterfaceProfileSpec struct {
+	nddv1.ResourceSpec `json:",inline"`
+	// Properties define the properties of the InterfaceProfile
+	Properties *InterfaceProfileProperties `json:"properties,omitempty"`
+}
+
+// A InterfaceProfileStatus represents the observed state of a InterfaceProfile.
+type InterfaceProfileStatus struct {
+	nddv1.ResourceStatus `json:",inline"`
+}
+
+// InterfaceProfileProperties define how the interfaces of a platform are named
+// when they are used in a fabric
+type InterfaceProfileProperties struct {
+	VendorType targetv1.VendorType `json:"vendorType,omitempty"`
+	Platform   string              `json:"platform,omitempty"`
+	// number of physical ports of the platform
+	// +kubebuilder:validation:Minimum=1
+	Ports uint32 `json:"ports"`
+	// format of the interface name, %d is replaced by the port number
+	// +kubebuilder:default="int-1/%d"
+	InterfaceNameFormat string `json:"interfaceNameFormat,omitempty"`
+	// number of breakout interfaces per physical port, 0 or 1 means no breakout
+	// +kubebuilder:validation:Maximum=8
+	Breakout uint32 `json:"breakout,omitempty"`
+	// format of the breakout interface name, the %d's are replaced by the port
+	// number and the breakout number
+	// +kubebuilder:default="int-1/%d/%d"
+	BreakoutInterfaceNameFormat string `json:"breakoutInterfaceNameFormat,omitempty"`
+	// first port used for the uplinks to the next tier
+	// the downlinks start at port 1
+	FirstUplinkPort uint32 `json:"firstUplinkPort,omitempty"`
+	// offsets applied to the uplink index per position, an offset
+	// takes precedence over the first uplink port
+	PositionOffsets []*InterfaceProfilePositionOffset `json:"positionOffsets,omitempty"`
+}
+
+type InterfaceProfilePositionOffset struct {
+	Position Position `json:"position"`
+	Offset   uint32   `json:"offset"`
+}
+
+// +kubebuilder:object:root=true
+
+// InterfaceProfile is the Schema for the InterfaceProfile API
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VENDORTYPE",type="string",JSONPath=".spec.properties.vendorType"
+// +kubebuilder:printcolumn:name="PLATFORM",type="string",JSONPath=".spec.properties.platform"
+// +kubebuilder:printcolumn:name="PORTS",type="integer",JSONPath=".spec.properties.ports"
+// +kubebuilder:printcolumn:name="FORMAT",type="string",JSONPath=".spec.properties.interfaceNameFormat"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:categories={yndd,topo}
+type InterfaceProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InterfaceProfileSpec   `json:"spec,omitempty"`
+	Status InterfaceProfileStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// InterfaceProfileList contains a list of InterfaceProfiles
+type InterfaceProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InterfaceProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InterfaceProfile{}, &InterfaceProfileList{})
+}
+
+// InterfaceProfile type metadata.
+var (
+	InterfaceProfileKind             = reflect.TypeOf(InterfaceProfile{}).Name()
+	InterfaceProfileGroupKind        = schema.GroupKind{Group: Group, Kind: InterfaceProfileKind}.String()
+	InterfaceProfileKindAPIVersion   = InterfaceProfileKind + "." + GroupVersion.String()
+	InterfaceProfileGroupVersionKind = GroupVersion.WithKind(InterfaceProfileKind)
+)
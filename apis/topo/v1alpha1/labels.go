@@ -0,0 +1,35 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Labels set on the resources rendered for a topology, they allow to select
+// the nodes and links of a topology.
+const (
+	LabelKeyOrganization     = "org.yndd.io/organization"
+	LabelKeyDeployment       = "org.yndd.io/deployment"
+	LabelKeyAvailabilityZone = "org.yndd.io/availabilityzone"
+	LabelKeyTopology         = "org.yndd.io/topology"
+)
+
+// Labels set on the nodes rendered from a fabric
+const (
+	LabelKeyTopologyPosition   = "topology.yndd.io/position"
+	LabelKeyTopologyNodeIndex  = "topology.yndd.io/NodeIndex"
+	LabelKeyTopologyPodIndex   = "topology.yndd.io/PodIndex"
+	LabelKeyTopologyPlatform   = "topology.yndd.io/Platform"
+	LabelKeyTopologyVendorType = "topology.yndd.io/VendorType"
+)
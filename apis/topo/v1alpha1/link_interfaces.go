@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
 	"github.com/yndd/app-runtime/pkg/odns"
 	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
 )
@@ -213,267 +215,158 @@ func (x *Link) GetDescription() string {
 	return *x.Spec.Properties.Description
 }
 
+func (x *Link) SetOrganization(s string) {
+	x.Status.SetOrganization(s)
+}
+
+func (x *Link) SetDeployment(s string) {
+	x.Status.SetDeployment(s)
+}
+
+func (x *Link) SetAvailabilityZone(s string) {
+	x.Status.SetAvailabilityZone(s)
+}
+*/
+
+func (x *Link) getEndpoint(i int) *Endpoints {
+	if x.Spec.Properties == nil || len(x.Spec.Properties.Endpoints) <= i || x.Spec.Properties.Endpoints[i] == nil {
+		return &Endpoints{}
+	}
+	return x.Spec.Properties.Endpoints[i]
+}
+
 func (x *Link) GetTags() map[string]string {
 	s := make(map[string]string)
-	if reflect.ValueOf(x.Spec.Properties.Tag).IsZero() {
+	if x.Spec.Properties == nil {
 		return s
 	}
-	for _, tag := range x.Spec.Properties.Tag {
-		s[*tag.Key] = *tag.Value
+	for k, v := range x.Spec.Properties.Tag {
+		s[k] = v
 	}
 	return s
 }
 
-func (x *Link) GetEndpoints() []*LinkEndpoints {
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
+func (x *Link) GetEndpoints() []*Endpoints {
+	if x.Spec.Properties == nil {
 		return nil
 	}
 	return x.Spec.Properties.Endpoints
 }
 
 func (x *Link) GetEndpointANodeName() string {
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
-		return ""
-	}
-	return *x.Spec.Properties.Endpoints[0].NodeName
+	return x.getEndpoint(0).NodeName
 }
 
 func (x *Link) GetEndpointBNodeName() string {
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
-		return ""
-	}
-	return *x.Spec.Properties.Endpoints[1].NodeName
+	return x.getEndpoint(1).NodeName
 }
 
 func (x *Link) GetEndpointAInterfaceName() string {
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
-		return ""
-	}
-	return *x.Spec.Properties.Endpoints[0].InterfaceName
+	return x.getEndpoint(0).InterfaceName
 }
 
 func (x *Link) GetEndpointBInterfaceName() string {
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
-		return ""
-	}
-	return *x.Spec.Properties.Endpoints[1].InterfaceName
+	return x.getEndpoint(1).InterfaceName
 }
 
 func (x *Link) GetEndpointATag() map[string]string {
 	s := make(map[string]string)
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
-		return s
-	}
-	for _, tag := range x.Spec.Properties.Endpoints[0].Tag {
-		s[*tag.Key] = *tag.Value
+	for k, v := range x.getEndpoint(0).Tag {
+		s[k] = v
 	}
 	return s
 }
 
 func (x *Link) GetEndpointBTag() map[string]string {
 	s := make(map[string]string)
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
-		return s
-	}
-	for _, tag := range x.Spec.Properties.Endpoints[1].Tag {
-		s[*tag.Key] = *tag.Value
+	for k, v := range x.getEndpoint(1).Tag {
+		s[k] = v
 	}
 	return s
 }
 
-func (x *Link) GetEndpointATagRaw() []*nddv1.Tag {
-	s := make([]*nddv1.Tag, 0)
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
-		return s
-	}
-	return x.Spec.Properties.Endpoints[0].Tag
-}
-
-func (x *Link) GetEndpointBTagRaw() []*nddv1.Tag {
-	s := make([]*nddv1.Tag, 0)
-	if reflect.ValueOf(x.Spec.Properties.Endpoints).IsZero() {
-		return s
-	}
-	return x.Spec.Properties.Endpoints[1].Tag
-}
-
-func (x *Link) GetEndPointAKind() string {
-	if n, ok := x.GetEndpointATag()[KeyLinkEPKind]; ok {
-		return n
-	}
-	// default
-	return "infra"
-}
-
-func (x *Link) GetEndPointBKind() string {
-	if n, ok := x.GetEndpointBTag()[KeyLinkEPKind]; ok {
-		return n
-	}
-	// default
-	return "infra"
-}
-
-func (x *Link) GetEndPointAGroup() string {
-	if n, ok := x.GetEndpointATag()[KeyLinkEPGroup]; ok {
-		return n
-	}
-	// default
-	return ""
-}
-
-func (x *Link) GetEndPointBGroup() string {
-	if n, ok := x.GetEndpointBTag()[KeyLinkEPGroup]; ok {
-		return n
-	}
-	// default
-	return ""
-}
-
 func (x *Link) GetEndPointAMultiHoming() bool {
-	if n, ok := x.GetEndpointATag()[KeyLinkEPMultiHoming]; ok {
-		return n == "true"
-	}
-	// default
-	return false
+	return x.getEndpoint(0).MultiHoming
 }
 
 func (x *Link) GetEndPointBMultiHoming() bool {
-	if n, ok := x.GetEndpointBTag()[KeyLinkEPMultiHoming]; ok {
-		return n == "true"
-	}
-	// default
-	return false
+	return x.getEndpoint(1).MultiHoming
 }
 
 func (x *Link) GetEndPointAMultiHomingName() string {
-	if n, ok := x.GetEndpointATag()[KeyLinkEPMultiHomingName]; ok {
-		return n
-	}
-	// default
-	return ""
+	return x.getEndpoint(0).MultiHomingName
 }
 
 func (x *Link) GetEndPointBMultiHomingName() string {
-	if n, ok := x.GetEndpointBTag()[KeyLinkEPMultiHomingName]; ok {
-		return n
-	}
-	// default
-	return ""
+	return x.getEndpoint(1).MultiHomingName
 }
 
 func (x *Link) GetLacpFallbackA() bool {
-	if _, ok := x.GetEndpointATag()[KeyLinkEPLacpFallback]; ok {
-		return x.GetTags()[KeyLinkEPLacpFallback] == "true"
-	}
-	// default
-	return false
+	return x.getEndpoint(0).LacpFallback
 }
 
 func (x *Link) GetLacpFallbackB() bool {
-	if _, ok := x.GetEndpointBTag()[KeyLinkEPLacpFallback]; ok {
-		return x.GetTags()[KeyLinkEPLacpFallback] == "true"
-	}
-	// default
-	return false
+	return x.getEndpoint(1).LacpFallback
 }
 
 func (x *Link) GetLagMember() bool {
-	if _, ok := x.GetTags()[KeyLinkLagMember]; ok {
-		return x.GetTags()[KeyLinkLagMember] == "true"
+	if x.Spec.Properties == nil {
+		return false
 	}
-	// default is false
-	return false
+	return x.Spec.Properties.LagMember
 }
 
 func (x *Link) GetLag() bool {
-	if _, ok := x.GetTags()[KeyLinkLag]; ok {
-		return x.GetTags()[KeyLinkLag] == "true"
+	if x.Spec.Properties == nil {
+		return false
 	}
-	// default is false
-	return false
+	return x.Spec.Properties.Lag
 }
 
 func (x *Link) GetLacp() bool {
-	if _, ok := x.GetTags()[KeyLinkLacp]; ok {
-		return x.GetTags()[KeyLinkLacp] == "true"
+	if x.Spec.Properties == nil {
+		return false
 	}
-	// default is true
-	return true
+	return x.Spec.Properties.Lacp
 }
 
 func (x *Link) GetLagAName() string {
-	if n, ok := x.GetEndpointATag()[KeyLinkEPLagName]; ok {
-		return n
-	}
-	return ""
+	return x.getEndpoint(0).LagName
 }
 
 func (x *Link) GetLagBName() string {
-	if n, ok := x.GetEndpointBTag()[KeyLinkEPLagName]; ok {
-		return n
-	}
-	return ""
-}
-
-func (x *Link) InitializeResource() error {
-	return nil
+	return x.getEndpoint(1).LagName
 }
 
+// AddEndPointATag adds or updates a tag of endpoint A
 func (x *Link) AddEndPointATag(key string, value string) {
-	for _, tag := range x.Spec.Properties.Endpoints[0].Tag {
-		if *tag.Key == key {
-			tag.Value = &value
-			return
-		}
+	ep := x.getEndpoint(0)
+	if ep.Tag == nil {
+		ep.Tag = map[string]string{}
 	}
-	// if not found append
-	x.Spec.Properties.Endpoints[0].Tag = append(x.Spec.Properties.Endpoints[0].Tag,
-		&nddv1.Tag{
-			Key:   &key,
-			Value: &value,
-		})
+	ep.Tag[key] = value
 }
 
+// AddEndPointBTag adds or updates a tag of endpoint B
 func (x *Link) AddEndPointBTag(key string, value string) {
-	for _, tag := range x.Spec.Properties.Endpoints[1].Tag {
-		if *tag.Key == key {
-			tag.Value = &value
-			return
-		}
+	ep := x.getEndpoint(1)
+	if ep.Tag == nil {
+		ep.Tag = map[string]string{}
 	}
-	// if not found append
-	x.Spec.Properties.Endpoints[1].Tag = append(x.Spec.Properties.Endpoints[1].Tag,
-		&nddv1.Tag{
-			Key:   &key,
-			Value: &value,
-		})
+	ep.Tag[key] = value
 }
 
+// DeleteEndPointATag deletes a tag of endpoint A if the value matches
 func (x *Link) DeleteEndPointATag(key string, value string) {
-	found := false
-	var idx int
-	for i, tag := range x.Spec.Properties.Endpoints[0].Tag {
-		if *tag.Key == key && *tag.Value == value {
-			idx = i
-			found = true
-		}
-	}
-	if found {
-		x.Spec.Properties.Endpoints[0].Tag = append(x.Spec.Properties.Endpoints[0].Tag[:idx], x.Spec.Properties.Endpoints[0].Tag[idx+1:]...)
+	if v, ok := x.getEndpoint(0).Tag[key]; ok && v == value {
+		delete(x.getEndpoint(0).Tag, key)
 	}
 }
 
+// DeleteEndPointBTag deletes a tag of endpoint B if the value matches
 func (x *Link) DeleteEndPointBTag(key string, value string) {
-	found := false
-	var idx int
-	for i, tag := range x.Spec.Properties.Endpoints[1].Tag {
-		if *tag.Key == key && *tag.Value == value {
-			idx = i
-			found = true
-		}
-	}
-	if found {
-		x.Spec.Properties.Endpoints[1].Tag = append(x.Spec.Properties.Endpoints[1].Tag[:idx], x.Spec.Properties.Endpoints[1].Tag[idx+1:]...)
+	if v, ok := x.getEndpoint(1).Tag[key]; ok && v == value {
+		delete(x.getEndpoint(1).Tag, key)
 	}
 }
 
@@ -488,18 +381,15 @@ func (x *Link) SetDeployment(s string) {
 func (x *Link) SetAvailabilityZone(s string) {
 	x.Status.SetAvailabilityZone(s)
 }
-*/
-
-func (x *Link) SetOrganization(s string) {
-	x.Status.SetOrganization(s)
-}
 
-func (x *Link) SetDeployment(s string) {
-	x.Status.SetDeployment(s)
-}
+const (
+	LogicalSingleHomedLinkPrefix = "logical-sh-link"
+	LogicalMultiHomedLinkPrefix  = "logical-mh-link"
+)
 
-func (x *Link) SetAvailabilityZone(s string) {
-	x.Status.SetAvailabilityZone(s)
+// LogicalSingleHomedLinkName returns the name of the logical link of a single-homed lag
+func LogicalSingleHomedLinkName(nodeNameA, lagNameA, nodeNameB, lagNameB string) string {
+	return strings.Join([]string{LogicalSingleHomedLinkPrefix, nodeNameA, lagNameA, nodeNameB, lagNameB}, "-")
 }
 
 func (x *Link) SetResourceName(s string) {
@@ -90,6 +90,7 @@ const (
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="SYNC",type="string",JSONPath=".status.conditions[?(@.kind=='Synced')].status"
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.conditions[?(@.kind=='Ready')].status"
+// +kubebuilder:printcolumn:name="HEALTHY",type="string",JSONPath=".status.conditions[?(@.kind=='Healthy')].status"
 // +kubebuilder:printcolumn:name="ORG",type="string",JSONPath=".status.oda.organization"
 // +kubebuilder:printcolumn:name="DEP",type="string",JSONPath=".status.oda.deployment"
 // +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".status.oda.availabilityZone"
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// validEndpointKinds are the endpoint kinds allowed on a link, an empty kind is unknown
+var validEndpointKinds = []string{
+	string(EndpointKindUnknown),
+	string(EndpointKindInfra),
+	string(EndpointKindLoop),
+	string(EndpointKindExternal),
+	string(EndpointKindOob),
+}
+
+func (x *Link) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(x).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-topo-yndd-io-v1alpha1-link,mutating=false,failurePolicy=fail,sideEffects=None,groups=topo.yndd.io,resources=links,verbs=create;update,versions=v1alpha1,name=vlink.topo.yndd.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Link{}
+
+// ValidateCreate implements webhook.Validator
+func (x *Link) ValidateCreate() error {
+	return x.validate()
+}
+
+// ValidateUpdate implements webhook.Validator
+func (x *Link) ValidateUpdate(old runtime.Object) error {
+	return x.validate()
+}
+
+// ValidateDelete implements webhook.Validator
+func (x *Link) ValidateDelete() error {
+	return nil
+}
+
+func (x *Link) validate() error {
+	fldPath := field.NewPath("spec", "properties")
+	allErrs := field.ErrorList{}
+
+	if x.Spec.Properties == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "a link requires properties with 2 endpoints"))
+		return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: LinkKind}, x.Name, allErrs)
+	}
+
+	if len(x.Spec.Properties.Endpoints) != 2 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("endpoints"), len(x.Spec.Properties.Endpoints), "a link requires exactly 2 endpoints"))
+	}
+	for i, ep := range x.Spec.Properties.Endpoints {
+		epPath := fldPath.Child("endpoints").Index(i)
+		// the multi-homed endpoint of a logical link spans multiple nodes
+		if ep.NodeName == "" && !(x.Spec.Properties.Lag && ep.MultiHoming) {
+			allErrs = append(allErrs, field.Required(epPath.Child("nodeName"), "an endpoint requires a nodeName"))
+		}
+		if ep.MultiHoming && ep.MultiHomingName == "" {
+			allErrs = append(allErrs, field.Required(epPath.Child("multiHomingName"), "a multi-homed endpoint requires a multiHomingName"))
+		}
+		if x.Spec.Properties.LagMember && ep.LagName == "" {
+			allErrs = append(allErrs, field.Required(epPath.Child("lagName"), "an endpoint of a lag member requires a lagName"))
+		}
+		if ep.Kind != "" && !isValidEndpointKind(ep.Kind) {
+			allErrs = append(allErrs, field.NotSupported(epPath.Child("kind"), ep.Kind, validEndpointKinds))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: LinkKind}, x.Name, allErrs)
+}
+
+func isValidEndpointKind(k EndpointKindProperties) bool {
+	for _, vk := range validEndpointKinds {
+		if string(k) == vk {
+			return true
+		}
+	}
+	return false
+}
@@ -153,12 +153,12 @@ func (x *Node) GetTopologyName() string {
 	return odns.Name2OdnsTopoResource(x.GetName()).GetTopologyName()
 }
 
-/*
-
 func (x *Node) GetNodeName() string {
 	return odns.Name2OdnsTopoResource(x.GetName()).GetResourceName()
 }
 
+/*
+
 func (x *Node) GetVendorType() string {
 	if reflect.ValueOf(x.Spec.Properties.VendorType).IsZero() {
 		return ""
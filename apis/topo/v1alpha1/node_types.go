@@ -35,6 +35,8 @@ type NodeSpec struct {
 // A NodeStatus represents the observed state of a node.
 type NodeStatus struct {
 	nddv1.ResourceStatus `json:",inline"`
+	// Properties holds the properties of the node with the defaults of the topology applied
+	Properties *NodeProperties `json:"properties,omitempty"`
 }
 
 // NodeProperties struct
@@ -57,6 +59,7 @@ const (
 	PositionLeaf       Position = "leaf"
 	PositionSpine      Position = "spine"
 	PositionSuperspine Position = "superspine"
+	PositionBorderLeaf Position = "borderleaf"
 	PositionDcgw       Position = "dcgw"
 	PositionWan        Position = "wan"
 	PositionCpe        Position = "cpe"
@@ -70,13 +73,15 @@ const (
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="SYNC",type="string",JSONPath=".status.conditions[?(@.kind=='Synced')].status"
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.conditions[?(@.kind=='Ready')].status"
+// +kubebuilder:printcolumn:name="HEALTHY",type="string",JSONPath=".status.conditions[?(@.kind=='Healthy')].status"
 // +kubebuilder:printcolumn:name="ORG",type="string",JSONPath=".status.oda.organization"
 // +kubebuilder:printcolumn:name="DEP",type="string",JSONPath=".status.oda.deployment"
 // +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".status.oda.availabilityZone"
 // +kubebuilder:printcolumn:name="TOPO",type="string",JSONPath=".status.oda.resourceName"
-// +kubebuilder:printcolumn:name="VENDORTYPE",type="string",JSONPath=".spec.properties.vendorType"
-// +kubebuilder:printcolumn:name="PLATFORM",type="string",JSONPath="..spec.properties.platform"
+// +kubebuilder:printcolumn:name="VENDORTYPE",type="string",JSONPath=".status.properties.vendorType"
+// +kubebuilder:printcolumn:name="PLATFORM",type="string",JSONPath=".status.properties.platform"
 // +kubebuilder:printcolumn:name="POSITION",type="string",JSONPath="..spec.properties.position"
+// +kubebuilder:printcolumn:name="TARGET",type="string",JSONPath=".spec.targetRef.name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:categories={yndd,topo}
 type Node struct {
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// validPositions are the positions allowed on a node, an empty position is unknown
+var validPositions = []string{
+	string(PositionUnknown),
+	string(PositionLeaf),
+	string(PositionSpine),
+	string(PositionSuperspine),
+	string(PositionBorderLeaf),
+	string(PositionDcgw),
+	string(PositionWan),
+	string(PositionCpe),
+	string(PositionServer),
+	string(PositionInfra),
+}
+
+func (x *Node) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(x).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-topo-yndd-io-v1alpha1-node,mutating=false,failurePolicy=fail,sideEffects=None,groups=topo.yndd.io,resources=nodes,verbs=create;update,versions=v1alpha1,name=vnode.topo.yndd.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Node{}
+
+// ValidateCreate implements webhook.Validator
+func (x *Node) ValidateCreate() error {
+	return x.validate()
+}
+
+// ValidateUpdate implements webhook.Validator
+func (x *Node) ValidateUpdate(old runtime.Object) error {
+	return x.validate()
+}
+
+// ValidateDelete implements webhook.Validator
+func (x *Node) ValidateDelete() error {
+	return nil
+}
+
+func (x *Node) validate() error {
+	if x.Spec.Properties == nil || x.Spec.Properties.Position == "" || x.Spec.Properties.Position.IsValid() {
+		return nil
+	}
+	allErrs := field.ErrorList{
+		field.NotSupported(field.NewPath("spec", "properties", "position"), x.Spec.Properties.Position, validPositions),
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: NodeKind}, x.Name, allErrs)
+}
+
+// IsValid returns true if the position is one of the known positions
+func (x Position) IsValid() bool {
+	for _, p := range validPositions {
+		if string(x) == p {
+			return true
+		}
+	}
+	return false
+}
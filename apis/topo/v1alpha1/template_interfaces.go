@@ -139,14 +139,99 @@ func (x *FabricTemplate) CheckTemplate(master bool) error {
 	if !master && len(x.Pod) != 1 {
 		return fmt.Errorf("a child template can only have 1 pod defined")
 	}
+	names := map[string]struct{}{}
 	for _, p := range x.Pod {
 		if err := p.CheckPodTemplate(master); err != nil {
 			return err
 		}
+		if p.HasReference() {
+			continue
+		}
+		if p.Name != "" {
+			if _, ok := names[p.Name]; ok {
+				return fmt.Errorf("pod template name %s is not unique", p.Name)
+			}
+			names[p.Name] = struct{}{}
+		}
 	}
 	return nil
 }
 
+// GetKey returns the key of a native pod template with its position p in the
+// template, a named pod template is identified by its name. The pod index of an
+// unnamed pod template is kept by the allocation of its position, so it changes
+// when the pod templates before it are removed or reordered.
+func (x *PodTemplate) GetKey(p int) string {
+	if x.Name != "" {
+		return "pod:" + x.Name
+	}
+	return fmt.Sprintf("pod%d", p+1)
+}
+
+// SetDefaults sets the defaults the api server applies to a template, it is used
+// for templates which are not read from the api server
+func (x *FabricTemplate) SetDefaults() {
+	if x.MaxUplinksTier2ToTier1 == 0 {
+		x.MaxUplinksTier2ToTier1 = 1
+	}
+	if x.MaxUplinksTier3ToTier2 == 0 {
+		x.MaxUplinksTier3ToTier2 = 1
+	}
+	if x.Planes != nil && x.Planes.Mode == "" {
+		x.Planes.Mode = PlaneModePlane
+	}
+}
+
+// IsChildTemplate returns true if the template only defines a single pod without
+// pod number, which is only valid as a template referred to by another template
+func (x *FabricTemplate) IsChildTemplate() bool {
+	return !x.HasTier1() && !x.HasBorderLeaf() && len(x.Pod) == 1 &&
+		!x.Pod[0].HasReference() && x.Pod[0].PodNumber == nil
+}
+
+// GetPodIndexes returns the index of the first pod of every pod template.
+// The pod templates with an explicit pod index are allocated first, the
+// others get the lowest free range of indexes in the order of the templates.
+func (x *FabricTemplate) GetPodIndexes() ([]uint32, error) {
+	podIndexes := make([]uint32, len(x.Pod))
+	used := map[uint32]int{}
+	for p, pod := range x.Pod {
+		if pod.PodIndex == nil {
+			continue
+		}
+		podIndexes[p] = *pod.PodIndex
+		for i := uint32(0); i < pod.GetPodNumber(); i++ {
+			if other, ok := used[*pod.PodIndex+i]; ok {
+				return nil, fmt.Errorf("pod index %d is used by pod template %d and %d", *pod.PodIndex+i, other+1, p+1)
+			}
+			used[*pod.PodIndex+i] = p
+		}
+	}
+	for p, pod := range x.Pod {
+		if pod.PodIndex != nil {
+			continue
+		}
+		start := uint32(1)
+		for !isPodIndexRangeFree(used, start, pod.GetPodNumber()) {
+			start++
+		}
+		podIndexes[p] = start
+		for i := uint32(0); i < pod.GetPodNumber(); i++ {
+			used[start+i] = p
+		}
+	}
+	return podIndexes, nil
+}
+
+func isPodIndexRangeFree(used map[uint32]int, start, num uint32) bool {
+	for i := uint32(0); i < num; i++ {
+		if _, ok := used[start+i]; ok {
+			return false
+		}
+	}
+	return true
+}
+
 func (x *FabricTemplate) HasDefinitionReference() bool {
 	if x.Pod == nil {
 		return false
@@ -191,11 +276,11 @@ func (x *FabricTemplate) HasPodDefinition() bool {
 }
 
 func (x *FabricTemplate) HasTier1() bool {
-	return x.Tier1 == nil
+	return x.Tier1 != nil
 }
 
 func (x *FabricTemplate) HasBorderLeaf() bool {
-	return x.BorderLeaf == nil
+	return x.BorderLeaf != nil
 }
 
 func (x *PodTemplate) CheckPodTemplate(master bool) error {
@@ -235,7 +320,7 @@ func (x *PodTemplate) HasTemplateReference() bool {
 }
 
 func (x *PodTemplate) HasDefinitionReference() bool {
-	return x.TemplateReference != nil
+	return x.DefinitionReference != nil
 }
 
 func (x *PodTemplate) GetPodNumber() uint32 {
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func uint32Ptr(i uint32) *uint32 { return &i }
+
+func TestGetPodIndexes(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		pods    []*PodTemplate
+		want    []uint32
+		wantErr bool
+	}{
+		"Sequential": {
+			reason: "Pod templates without pod index should get the lowest free range in the order of the templates.",
+			pods: []*PodTemplate{
+				{PodNumber: uint32Ptr(2)},
+				{PodNumber: uint32Ptr(1)},
+			},
+			want: []uint32{1, 3},
+		},
+		"ExplicitIndexes": {
+			reason: "Pod templates with a pod index should get their pod index.",
+			pods: []*PodTemplate{
+				{PodNumber: uint32Ptr(2), PodIndex: uint32Ptr(5)},
+				{PodNumber: uint32Ptr(1), PodIndex: uint32Ptr(1)},
+			},
+			want: []uint32{5, 1},
+		},
+		"ReorderedExplicitIndexes": {
+			reason: "Reordered pod templates with a pod index should keep their pod index.",
+			pods: []*PodTemplate{
+				{PodNumber: uint32Ptr(1), PodIndex: uint32Ptr(1)},
+				{PodNumber: uint32Ptr(2), PodIndex: uint32Ptr(5)},
+			},
+			want: []uint32{1, 5},
+		},
+		"ExplicitIndexesFirst": {
+			reason: "Pod templates with a pod index should be allocated before the others, independent of their order.",
+			pods: []*PodTemplate{
+				{PodNumber: uint32Ptr(2)},
+				{PodNumber: uint32Ptr(1), PodIndex: uint32Ptr(2)},
+				{PodNumber: uint32Ptr(1)},
+			},
+			want: []uint32{3, 2, 1},
+		},
+		"ReorderedMixed": {
+			reason: "A reordered pod template with a pod index should keep its pod index, the others fill the free ranges.",
+			pods: []*PodTemplate{
+				{PodNumber: uint32Ptr(1)},
+				{PodNumber: uint32Ptr(1), PodIndex: uint32Ptr(2)},
+				{PodNumber: uint32Ptr(2)},
+			},
+			want: []uint32{1, 2, 3},
+		},
+		"Overlap": {
+			reason: "Pod templates with overlapping pod indexes should return an error.",
+			pods: []*PodTemplate{
+				{PodNumber: uint32Ptr(2), PodIndex: uint32Ptr(1)},
+				{PodNumber: uint32Ptr(1), PodIndex: uint32Ptr(2)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			x := &FabricTemplate{Pod: tc.pods}
+			got, err := x.GetPodIndexes()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\nGetPodIndexes(): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("\n%s\nGetPodIndexes(): want %v, got %v", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
@@ -38,9 +38,16 @@ type TemplateSubnet struct {
 
 type FabricTemplate struct {
 	// superspine
-	Tier1      *TierTemplate  `json:"tier1,omitempty"`
-	BorderLeaf *TierTemplate  `json:"borderLeaf,omitempty"`
-	Pod        []*PodTemplate `json:"pod,omitempty"`
+	Tier1      *TierTemplate `json:"tier1,omitempty"`
+	BorderLeaf *TierTemplate `json:"borderLeaf,omitempty"`
+	// pod index the border leafs connect to using the spines of the pod
+	// when not set the border leafs connect to the superspines
+	// +kubebuilder:validation:Minimum=1
+	BorderLeafPod *uint32 `json:"borderLeafPod,omitempty"`
+	// planes define how the spines connect to the superspines
+	// when not set every spine index defines a plane with tier1.num superspines
+	Planes *PlaneTemplate `json:"planes,omitempty"`
+	Pod    []*PodTemplate `json:"pod,omitempty"`
 	// max number of uplink per node to the next tier
 	// default should be 1 and max is 4
 	// +kubebuilder:validation:Minimum=1
@@ -55,13 +62,58 @@ type FabricTemplate struct {
 	MaxUplinksTier3ToTier2 uint32 `json:"maxUplinksTier3ToTier2,omitempty"`
 }
 
+type PlaneTemplate struct {
+	// number of superspine planes
+	// +kubebuilder:validation:Minimum=1
+	PlaneNumber uint32 `json:"num"`
+	// number of superspines per plane, when not set tier1.num is used
+	SuperspinesPerPlane uint32 `json:"superspinesPerPlane,omitempty"`
+	// mode plane connects a spine to the superspines of its planes
+	// mode fullMesh connects every spine to every superspine
+	// +kubebuilder:validation:Enum=plane;fullMesh
+	// +kubebuilder:default=plane
+	Mode PlaneMode `json:"mode,omitempty"`
+	// explicit spine to plane assignments, a spine without assignment
+	// is assigned to plane ((spine index - 1) % num) + 1
+	SpineAssignments []*SpinePlaneAssignment `json:"spineAssignments,omitempty"`
+}
+
+type PlaneMode string
+
+// PlaneMode enums.
+const (
+	PlaneModePlane    PlaneMode = "plane"
+	PlaneModeFullMesh PlaneMode = "fullMesh"
+)
+
+type SpinePlaneAssignment struct {
+	// pod index of the spine, when not set the assignment applies to the spine in every pod
+	// +kubebuilder:validation:Minimum=1
+	PodIndex *uint32 `json:"pod,omitempty"`
+	// index of the spine within the pod
+	// +kubebuilder:validation:Minimum=1
+	SpineIndex uint32 `json:"spine"`
+	// planes the spine connects to
+	// +kubebuilder:validation:MinItems=1
+	Planes []uint32 `json:"planes"`
+}
+
 type PodTemplate struct {
+	// name of a native pod template, it identifies the pod template when the pod indexes
+	// are allocated, so adding, removing or reordering pod templates does not rename the
+	// pods. Unnamed pod templates are identified by their position
+	Name string `json:"name,omitempty"`
 	// number of pods defined based on this template
 	// no default since templates should not define the pod number
 	// default should be 1 and max is 16
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=16
 	PodNumber *uint32 `json:"num,omitempty"`
+	// index of the first pod defined by the template, the pods are indexed from
+	// podIndex to podIndex + num - 1 so reordering the pod templates does not
+	// rename the pods. When not set the pods get the lowest free indexes
+	// +kubebuilder:validation:Minimum=1
+	PodIndex *uint32 `json:"podIndex,omitempty"`
 	// Tier2 template, that defines the spine parameters in the pod definition
 	Tier2 *TierTemplate `json:"tier2,omitempty"`
 	// Tier3 template, that defines the leaf parameters in the pod definition
@@ -83,6 +135,10 @@ type TierTemplate struct {
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=4
 	UplinksPerNode uint32 `json:"uplinkPerNode,omitempty"`
+	// bundle the uplinks of a node to a node of the next tier in a LAG with LACP,
+	// a single uplink is not bundled
+	// +kubebuilder:default=false
+	Lag bool `json:"lag,omitempty"`
 }
 
 type FabricTierVendorInfo struct {
@@ -109,6 +165,34 @@ type TemplateStatus struct {
 	nddv1.ResourceStatus `json:",inline"`
 	TopologyName         string `json:"topology-name,omitempty"`
 	//Topology                *NddrTopologyTopology `json:"topology,omitempty"`
+	// Summary of the fabric computed from the template
+	Summary *TemplateSummary `json:"summary,omitempty"`
+}
+
+// TemplateSummary summarizes the fabric a template renders
+type TemplateSummary struct {
+	// number of pods in the fabric
+	Pods uint32 `json:"pods,omitempty"`
+	// number of nodes per tier
+	Nodes []*TemplateSummaryTier `json:"nodes,omitempty"`
+	// total number of physical links in the fabric
+	Links uint32 `json:"links,omitempty"`
+	// required ports per platform
+	Platforms []*TemplateSummaryPlatform `json:"platforms,omitempty"`
+}
+
+type TemplateSummaryTier struct {
+	Position Position `json:"position"`
+	Nodes    uint32   `json:"nodes"`
+}
+
+type TemplateSummaryPlatform struct {
+	VendorType targetv1.VendorType `json:"vendorType,omitempty"`
+	Platform   string              `json:"platform,omitempty"`
+	// number of nodes using the platform
+	Nodes uint32 `json:"nodes"`
+	// highest port used on a node of the platform
+	RequiredPorts uint32 `json:"requiredPorts"`
 }
 
 // +kubebuilder:object:root=true
@@ -121,6 +205,8 @@ type TemplateStatus struct {
 // +kubebuilder:printcolumn:name="DEP",type="string",JSONPath=".status.oda[?(@.key=='deployment')].value"
 // +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".status.oda[?(@.key=='availabilityZone')].value"
 // +kubebuilder:printcolumn:name="TOPO",type="string",JSONPath=".status.oda[?(@.key=='resourceName')].value"
+// +kubebuilder:printcolumn:name="PODS",type="integer",JSONPath=".status.summary.pods"
+// +kubebuilder:printcolumn:name="LINKS",type="integer",JSONPath=".status.summary.links"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:categories={yndd,topo}
 type Template struct {
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (x *Template) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(x).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-topo-yndd-io-v1alpha1-template,mutating=false,failurePolicy=fail,sideEffects=None,groups=topo.yndd.io,resources=templates,verbs=create;update,versions=v1alpha1,name=vtemplate.topo.yndd.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Template{}
+
+// ValidateCreate implements webhook.Validator
+func (x *Template) ValidateCreate() error {
+	return x.validate()
+}
+
+// ValidateUpdate implements webhook.Validator
+func (x *Template) ValidateUpdate(old runtime.Object) error {
+	return x.validate()
+}
+
+// ValidateDelete implements webhook.Validator
+func (x *Template) ValidateDelete() error {
+	return nil
+}
+
+func (x *Template) validate() error {
+	ft := x.Spec.Properties.Fabric
+	if ft == nil {
+		return nil
+	}
+	fldPath := field.NewPath("spec", "properties", "fabric")
+	allErrs := field.ErrorList{}
+
+	master := !ft.IsChildTemplate()
+	if err := ft.CheckTemplate(master); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pod"), len(ft.Pod), err.Error()))
+	}
+
+	allErrs = append(allErrs, validateTierTemplate(fldPath.Child("tier1"), ft.Tier1)...)
+	allErrs = append(allErrs, validateTierTemplate(fldPath.Child("borderLeaf"), ft.BorderLeaf)...)
+	for i, pod := range ft.Pod {
+		allErrs = append(allErrs, validateTierTemplate(fldPath.Child("pod").Index(i).Child("tier2"), pod.Tier2)...)
+		allErrs = append(allErrs, validateTierTemplate(fldPath.Child("pod").Index(i).Child("tier3"), pod.Tier3)...)
+	}
+
+	if _, err := ft.GetPodIndexes(); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pod"), len(ft.Pod), err.Error()))
+	}
+
+	if ft.Planes != nil {
+		if !ft.HasTier1() {
+			allErrs = append(allErrs, field.Required(fldPath.Child("tier1"), "planes require a tier1"))
+		}
+		for i, sa := range ft.Planes.SpineAssignments {
+			for j, plane := range sa.Planes {
+				if plane == 0 || plane > ft.Planes.PlaneNumber {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("planes", "spineAssignments").Index(i).Child("planes").Index(j), plane,
+						fmt.Sprintf("planes are numbered from 1 to %d", ft.Planes.PlaneNumber)))
+				}
+			}
+		}
+	}
+
+	// the max uplinks of a child template are defined by the template referring to it
+	if master {
+		for i, pod := range ft.Pod {
+			podPath := fldPath.Child("pod").Index(i)
+			if pod.Tier3 != nil && pod.Tier3.UplinksPerNode > ft.MaxUplinksTier3ToTier2 {
+				allErrs = append(allErrs, field.Invalid(podPath.Child("tier3", "uplinkPerNode"), pod.Tier3.UplinksPerNode,
+					fmt.Sprintf("must be less than or equal to maxUplinksTier3ToTier2 %d", ft.MaxUplinksTier3ToTier2)))
+			}
+			if ft.HasTier1() && pod.Tier2 != nil && pod.Tier2.UplinksPerNode > ft.MaxUplinksTier2ToTier1 {
+				allErrs = append(allErrs, field.Invalid(podPath.Child("tier2", "uplinkPerNode"), pod.Tier2.UplinksPerNode,
+					fmt.Sprintf("must be less than or equal to maxUplinksTier2ToTier1 %d", ft.MaxUplinksTier2ToTier1)))
+			}
+		}
+		if ft.HasBorderLeaf() {
+			if ft.BorderLeafPod != nil {
+				if ft.BorderLeaf.UplinksPerNode > ft.MaxUplinksTier3ToTier2 {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("borderLeaf", "uplinkPerNode"), ft.BorderLeaf.UplinksPerNode,
+						fmt.Sprintf("must be less than or equal to maxUplinksTier3ToTier2 %d", ft.MaxUplinksTier3ToTier2)))
+				}
+			} else {
+				if !ft.HasTier1() {
+					allErrs = append(allErrs, field.Required(fldPath.Child("tier1"), "borderLeaf requires a tier1 or a borderLeafPod to connect to"))
+				}
+				if ft.BorderLeaf.UplinksPerNode > ft.MaxUplinksTier2ToTier1 {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("borderLeaf", "uplinkPerNode"), ft.BorderLeaf.UplinksPerNode,
+						fmt.Sprintf("must be less than or equal to maxUplinksTier2ToTier1 %d", ft.MaxUplinksTier2ToTier1)))
+				}
+			}
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: TemplateKind}, x.Name, allErrs)
+}
+
+// validateTierTemplate checks a tier with nodes has a vendor
+func validateTierTemplate(fldPath *field.Path, t *TierTemplate) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if t == nil {
+		return allErrs
+	}
+	if t.NodeNumber > 0 && len(t.VendorInfo) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("vendorInfo"), "a tier with nodes requires at least 1 vendorInfo"))
+	}
+	return allErrs
+}
@@ -19,6 +19,8 @@ package v1alpha1
 import (
 	"github.com/yndd/app-runtime/pkg/odns"
 	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 /*
@@ -227,6 +229,46 @@ func (x *Topology) SetAvailabilityZone(s string) {
 }
 */
 
+// IsReconciled returns true if the topology is synced or only fails because its
+// nodes or links are degraded
+func (x *Topology) IsReconciled() bool {
+	if x.GetCondition(nddv1.ConditionKindSynced).Status == corev1.ConditionTrue {
+		return true
+	}
+	healthy := x.GetCondition(ConditionKindHealthy)
+	return healthy.Status == corev1.ConditionFalse && healthy.Reason == ConditionReasonDegraded
+}
+
+// GetDefaults returns the default node properties of the topology
+func (x *Topology) GetDefaults() *NodeProperties {
+	if x.Spec.Properties.Defaults == nil || x.Spec.Properties.Defaults.NodeProperties == nil {
+		return &NodeProperties{}
+	}
+	return x.Spec.Properties.Defaults.NodeProperties
+}
+
+// GetDefaultsTags returns the default tags of the nodes of the topology
+func (x *Topology) GetDefaultsTags() map[string]string {
+	s := make(map[string]string)
+	if x.Spec.Properties.Defaults == nil {
+		return s
+	}
+	for k, v := range x.Spec.Properties.Defaults.Tag {
+		s[k] = v
+	}
+	return s
+}
+
+// GetVendorTypeInfo returns the node properties of the vendor type
+func (x *Topology) GetVendorTypeInfo(vendorType targetv1.VendorType) *NodeProperties {
+	for _, vendorTypeInfo := range x.Spec.Properties.VendorTypeInfo {
+		if vendorTypeInfo != nil && vendorTypeInfo.VendorType == vendorType {
+			return vendorTypeInfo
+		}
+	}
+	return &NodeProperties{}
+}
+
 func (x *Topology) SetOrganization(s string) {
 	x.Status.SetOrganization(s)
 }
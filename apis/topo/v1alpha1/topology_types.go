@@ -34,6 +34,32 @@ type TopologySpec struct {
 // A TopologyStatus represents the observed state of a Topology.
 type TopologyStatus struct {
 	nddv1.ResourceStatus `json:",inline"`
+	// Members summarizes the nodes and links of the topology
+	Members *TopologyMembers `json:"members,omitempty"`
+}
+
+// TopologyMembers summarizes the state of the nodes and links of a topology
+type TopologyMembers struct {
+	// number of nodes per position
+	Nodes []*TopologyMemberCount `json:"nodes,omitempty"`
+	// number of links per kind
+	Links []*TopologyMemberCount `json:"links,omitempty"`
+	// nodes and links which are not ready, limited to the first 50
+	NotReady []*TopologyMember `json:"notReady,omitempty"`
+}
+
+type TopologyMemberCount struct {
+	// position of the nodes or kind of the links
+	Name  string `json:"name"`
+	Total uint32 `json:"total"`
+	Ready uint32 `json:"ready"`
+}
+
+type TopologyMember struct {
+	// kind of the member, Node or Link
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"`
 }
 
 // TopologyProperties struct
@@ -44,8 +70,9 @@ type TopologyProperties struct {
 
 // TopologySpecDefaults struct
 type TopologyDefaults struct {
-	NodeProperties *NodeProperties   `json:",inline"`
-	Tag            map[string]string `json:"tag,omitempty"`
+	// the node properties are embedded such that they are inlined in json
+	*NodeProperties `json:",inline"`
+	Tag             map[string]string `json:"tag,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -54,6 +81,7 @@ type TopologyDefaults struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="SYNC",type="string",JSONPath=".status.conditions[?(@.kind=='Synced')].status"
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.conditions[?(@.kind=='Ready')].status"
+// +kubebuilder:printcolumn:name="HEALTHY",type="string",JSONPath=".status.conditions[?(@.kind=='Healthy')].status"
 // +kubebuilder:printcolumn:name="ORG",type="string",JSONPath=".status.oda.organization"
 // +kubebuilder:printcolumn:name="DEP",type="string",JSONPath=".status.oda.deployment"
 // +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".status.oda.availabilityZone"
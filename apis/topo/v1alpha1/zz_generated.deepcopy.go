@@ -22,9 +22,25 @@ limitations under the License.
 package v1alpha1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"github.com/yndd/ndd-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BorderLeafAllocation) DeepCopyInto(out *BorderLeafAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BorderLeafAllocation.
+func (in *BorderLeafAllocation) DeepCopy() *BorderLeafAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(BorderLeafAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Definition) DeepCopyInto(out *Definition) {
 	*out = *in
@@ -100,6 +116,66 @@ func (in *DefinitionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionPlan) DeepCopyInto(out *DefinitionPlan) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(DefinitionPlanChanges)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = new(DefinitionPlanChanges)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = new(FabricDiff)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionPlan.
+func (in *DefinitionPlan) DeepCopy() *DefinitionPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionPlanChanges) DeepCopyInto(out *DefinitionPlanChanges) {
+	*out = *in
+	if in.Create != nil {
+		in, out := &in.Create, &out.Create
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionPlanChanges.
+func (in *DefinitionPlanChanges) DeepCopy() *DefinitionPlanChanges {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionPlanChanges)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DefinitionProperties) DeepCopyInto(out *DefinitionProperties) {
 	*out = *in
@@ -125,6 +201,22 @@ func (in *DefinitionProperties) DeepCopyInto(out *DefinitionProperties) {
 			}
 		}
 	}
+	if in.VendorTypeInfo != nil {
+		in, out := &in.VendorTypeInfo, &out.VendorTypeInfo
+		*out = make([]*NodeProperties, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(NodeProperties)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(TopologyDefaults)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionProperties.
@@ -177,6 +269,47 @@ func (in *DefinitionSpec) DeepCopy() *DefinitionSpec {
 func (in *DefinitionStatus) DeepCopyInto(out *DefinitionStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(DefinitionPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make(map[string]*FabricAllocations, len(*in))
+		for key, val := range *in {
+			var outVal *FabricAllocations
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(FabricAllocations)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]*NodeBinding, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(NodeBinding)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.DigitalTwinReference != nil {
+		in, out := &in.DigitalTwinReference, &out.DigitalTwinReference
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.CablingPlanReference != nil {
+		in, out := &in.CablingPlanReference, &out.CablingPlanReference
+		*out = new(v1.Reference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionStatus.
@@ -227,6 +360,223 @@ func (in *Endpoints) DeepCopy() *Endpoints {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FabricAllocations) DeepCopyInto(out *FabricAllocations) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]*PodAllocation, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(PodAllocation)
+				**out = **in
+			}
+		}
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]*LinkAllocation, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(LinkAllocation)
+				**out = **in
+			}
+		}
+	}
+	if in.BorderLeafs != nil {
+		in, out := &in.BorderLeafs, &out.BorderLeafs
+		*out = make([]*BorderLeafAllocation, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(BorderLeafAllocation)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FabricAllocations.
+func (in *FabricAllocations) DeepCopy() *FabricAllocations {
+	if in == nil {
+		return nil
+	}
+	out := new(FabricAllocations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FabricDiff) DeepCopyInto(out *FabricDiff) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(FabricDiffNodes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = new(FabricDiffLinks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReindexedInterfaces != nil {
+		in, out := &in.ReindexedInterfaces, &out.ReindexedInterfaces
+		*out = make([]*FabricDiffInterface, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(FabricDiffInterface)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FabricDiff.
+func (in *FabricDiff) DeepCopy() *FabricDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(FabricDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FabricDiffInterface) DeepCopyInto(out *FabricDiffInterface) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FabricDiffInterface.
+func (in *FabricDiffInterface) DeepCopy() *FabricDiffInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(FabricDiffInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FabricDiffLabels) DeepCopyInto(out *FabricDiffLabels) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FabricDiffLabels.
+func (in *FabricDiffLabels) DeepCopy() *FabricDiffLabels {
+	if in == nil {
+		return nil
+	}
+	out := new(FabricDiffLabels)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FabricDiffLink) DeepCopyInto(out *FabricDiffLink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FabricDiffLink.
+func (in *FabricDiffLink) DeepCopy() *FabricDiffLink {
+	if in == nil {
+		return nil
+	}
+	out := new(FabricDiffLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FabricDiffLinks) DeepCopyInto(out *FabricDiffLinks) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Moved != nil {
+		in, out := &in.Moved, &out.Moved
+		*out = make([]*FabricDiffLink, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(FabricDiffLink)
+				**out = **in
+			}
+		}
+	}
+	if in.Relabeled != nil {
+		in, out := &in.Relabeled, &out.Relabeled
+		*out = make([]*FabricDiffLabels, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(FabricDiffLabels)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FabricDiffLinks.
+func (in *FabricDiffLinks) DeepCopy() *FabricDiffLinks {
+	if in == nil {
+		return nil
+	}
+	out := new(FabricDiffLinks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FabricDiffNodes) DeepCopyInto(out *FabricDiffNodes) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Relabeled != nil {
+		in, out := &in.Relabeled, &out.Relabeled
+		*out = make([]*FabricDiffLabels, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(FabricDiffLabels)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FabricDiffNodes.
+func (in *FabricDiffNodes) DeepCopy() *FabricDiffNodes {
+	if in == nil {
+		return nil
+	}
+	out := new(FabricDiffNodes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FabricTemplate) DeepCopyInto(out *FabricTemplate) {
 	*out = *in
@@ -240,6 +590,16 @@ func (in *FabricTemplate) DeepCopyInto(out *FabricTemplate) {
 		*out = new(TierTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BorderLeafPod != nil {
+		in, out := &in.BorderLeafPod, &out.BorderLeafPod
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Planes != nil {
+		in, out := &in.Planes, &out.Planes
+		*out = new(PlaneTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Pod != nil {
 		in, out := &in.Pod, &out.Pod
 		*out = make([]*PodTemplate, len(*in))
@@ -278,6 +638,143 @@ func (in *FabricTierVendorInfo) DeepCopy() *FabricTierVendorInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceProfile) DeepCopyInto(out *InterfaceProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceProfile.
+func (in *InterfaceProfile) DeepCopy() *InterfaceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InterfaceProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceProfileList) DeepCopyInto(out *InterfaceProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InterfaceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceProfileList.
+func (in *InterfaceProfileList) DeepCopy() *InterfaceProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InterfaceProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceProfilePositionOffset) DeepCopyInto(out *InterfaceProfilePositionOffset) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceProfilePositionOffset.
+func (in *InterfaceProfilePositionOffset) DeepCopy() *InterfaceProfilePositionOffset {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceProfilePositionOffset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceProfileProperties) DeepCopyInto(out *InterfaceProfileProperties) {
+	*out = *in
+	if in.PositionOffsets != nil {
+		in, out := &in.PositionOffsets, &out.PositionOffsets
+		*out = make([]*InterfaceProfilePositionOffset, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(InterfaceProfilePositionOffset)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceProfileProperties.
+func (in *InterfaceProfileProperties) DeepCopy() *InterfaceProfileProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceProfileProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceProfileSpec) DeepCopyInto(out *InterfaceProfileSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = new(InterfaceProfileProperties)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceProfileSpec.
+func (in *InterfaceProfileSpec) DeepCopy() *InterfaceProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceProfileStatus) DeepCopyInto(out *InterfaceProfileStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceProfileStatus.
+func (in *InterfaceProfileStatus) DeepCopy() *InterfaceProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Link) DeepCopyInto(out *Link) {
 	*out = *in
@@ -305,6 +802,21 @@ func (in *Link) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkAllocation) DeepCopyInto(out *LinkAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkAllocation.
+func (in *LinkAllocation) DeepCopy() *LinkAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LinkList) DeepCopyInto(out *LinkList) {
 	*out = *in
@@ -434,6 +946,26 @@ func (in *Node) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeBinding) DeepCopyInto(out *NodeBinding) {
+	*out = *in
+	if in.Mismatches != nil {
+		in, out := &in.Mismatches, &out.Mismatches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBinding.
+func (in *NodeBinding) DeepCopy() *NodeBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeList) DeepCopyInto(out *NodeList) {
 	*out = *in
@@ -513,6 +1045,11 @@ func (in *NodeSpec) DeepCopy() *NodeSpec {
 func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = new(NodeProperties)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
@@ -525,6 +1062,47 @@ func (in *NodeStatus) DeepCopy() *NodeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlaneTemplate) DeepCopyInto(out *PlaneTemplate) {
+	*out = *in
+	if in.SpineAssignments != nil {
+		in, out := &in.SpineAssignments, &out.SpineAssignments
+		*out = make([]*SpinePlaneAssignment, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SpinePlaneAssignment)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlaneTemplate.
+func (in *PlaneTemplate) DeepCopy() *PlaneTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PlaneTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodAllocation) DeepCopyInto(out *PodAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodAllocation.
+func (in *PodAllocation) DeepCopy() *PodAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(PodAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodTemplate) DeepCopyInto(out *PodTemplate) {
 	*out = *in
@@ -533,6 +1111,11 @@ func (in *PodTemplate) DeepCopyInto(out *PodTemplate) {
 		*out = new(uint32)
 		**out = **in
 	}
+	if in.PodIndex != nil {
+		in, out := &in.PodIndex, &out.PodIndex
+		*out = new(uint32)
+		**out = **in
+	}
 	if in.Tier2 != nil {
 		in, out := &in.Tier2, &out.Tier2
 		*out = new(TierTemplate)
@@ -565,6 +1148,31 @@ func (in *PodTemplate) DeepCopy() *PodTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpinePlaneAssignment) DeepCopyInto(out *SpinePlaneAssignment) {
+	*out = *in
+	if in.PodIndex != nil {
+		in, out := &in.PodIndex, &out.PodIndex
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Planes != nil {
+		in, out := &in.Planes, &out.Planes
+		*out = make([]uint32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinePlaneAssignment.
+func (in *SpinePlaneAssignment) DeepCopy() *SpinePlaneAssignment {
+	if in == nil {
+		return nil
+	}
+	out := new(SpinePlaneAssignment)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SupportServers) DeepCopyInto(out *SupportServers) {
 	*out = *in
@@ -708,6 +1316,11 @@ func (in *TemplateSpec) DeepCopy() *TemplateSpec {
 func (in *TemplateStatus) DeepCopyInto(out *TemplateStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(TemplateSummary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateStatus.
@@ -736,6 +1349,73 @@ func (in *TemplateSubnet) DeepCopy() *TemplateSubnet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSummary) DeepCopyInto(out *TemplateSummary) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]*TemplateSummaryTier, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TemplateSummaryTier)
+				**out = **in
+			}
+		}
+	}
+	if in.Platforms != nil {
+		in, out := &in.Platforms, &out.Platforms
+		*out = make([]*TemplateSummaryPlatform, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TemplateSummaryPlatform)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSummary.
+func (in *TemplateSummary) DeepCopy() *TemplateSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSummaryPlatform) DeepCopyInto(out *TemplateSummaryPlatform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSummaryPlatform.
+func (in *TemplateSummaryPlatform) DeepCopy() *TemplateSummaryPlatform {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSummaryPlatform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSummaryTier) DeepCopyInto(out *TemplateSummaryTier) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSummaryTier.
+func (in *TemplateSummaryTier) DeepCopy() *TemplateSummaryTier {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSummaryTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TierTemplate) DeepCopyInto(out *TierTemplate) {
 	*out = *in
@@ -848,6 +1528,84 @@ func (in *TopologyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyMember) DeepCopyInto(out *TopologyMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyMember.
+func (in *TopologyMember) DeepCopy() *TopologyMember {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyMemberCount) DeepCopyInto(out *TopologyMemberCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyMemberCount.
+func (in *TopologyMemberCount) DeepCopy() *TopologyMemberCount {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyMemberCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyMembers) DeepCopyInto(out *TopologyMembers) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]*TopologyMemberCount, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TopologyMemberCount)
+				**out = **in
+			}
+		}
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]*TopologyMemberCount, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TopologyMemberCount)
+				**out = **in
+			}
+		}
+	}
+	if in.NotReady != nil {
+		in, out := &in.NotReady, &out.NotReady
+		*out = make([]*TopologyMember, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TopologyMember)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyMembers.
+func (in *TopologyMembers) DeepCopy() *TopologyMembers {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyMembers)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologyProperties) DeepCopyInto(out *TopologyProperties) {
 	*out = *in
@@ -900,6 +1658,11 @@ func (in *TopologySpec) DeepCopy() *TopologySpec {
 func (in *TopologyStatus) DeepCopyInto(out *TopologyStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = new(TopologyMembers)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyStatus.
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intent
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/yndd/topology/internal/cabling"
+)
+
+const (
+	formatCSV      = "csv"
+	formatMarkdown = "markdown"
+
+	sheetCabling = "cabling"
+	sheetBOM     = "bom"
+)
+
+var (
+	cablingFiles     []string
+	cablingTemplate  string
+	cablingSheet     string
+	cablingFormat    string
+	cablingOutput    string
+	cablingNamespace string
+)
+
+// cablingCmd renders the cabling plan of the fabric of a template without cluster
+var cablingCmd = &cobra.Command{
+	Use:   "cabling",
+	Short: "render the cabling plan of a template",
+	Long: "render the cabling sheet or the bill of materials of the fabric of a template as CSV or Markdown. " +
+		"The files hold the template and the templates, definitions and interface profiles it refers to.",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		c, err := newOfflineClient(cablingFiles, cablingNamespace)
+		if err != nil {
+			return err
+		}
+		tmpl, err := getTemplate(ctx, c, cablingTemplate)
+		if err != nil {
+			return err
+		}
+		f, err := newOfflineFabric(ctx, c, tmpl)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render template %s", tmpl.GetName())
+		}
+		p := cabling.NewPlan()
+		f.ExportCablingPlan(p)
+
+		var b []byte
+		switch {
+		case cablingSheet == sheetCabling && cablingFormat == formatCSV:
+			b, err = p.CablingCSV()
+		case cablingSheet == sheetCabling && cablingFormat == formatMarkdown:
+			b = p.CablingMarkdown()
+		case cablingSheet == sheetBOM && cablingFormat == formatCSV:
+			b, err = p.BOMCSV()
+		case cablingSheet == sheetBOM && cablingFormat == formatMarkdown:
+			b = p.BOMMarkdown()
+		case cablingSheet != sheetCabling && cablingSheet != sheetBOM:
+			return errors.Errorf("unsupported sheet %s, supported sheets are %s and %s", cablingSheet, sheetCabling, sheetBOM)
+		default:
+			return errors.Errorf("unsupported format %s, supported formats are %s and %s", cablingFormat, formatCSV, formatMarkdown)
+		}
+		if err != nil {
+			return err
+		}
+		return writeOutput(cablingOutput, b)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cablingCmd)
+	cablingCmd.Flags().StringSliceVarP(&cablingFiles, "file", "f", []string{}, "Files with the template and the resources it refers to.")
+	cablingCmd.Flags().StringVarP(&cablingTemplate, "template", "t", "", "Name of the template to render, required when the files have multiple templates.")
+	cablingCmd.Flags().StringVarP(&cablingSheet, "sheet", "s", sheetCabling, "Sheet to render: cabling or bom.")
+	cablingCmd.Flags().StringVarP(&cablingFormat, "format", "", formatCSV, "Output format: csv or markdown.")
+	cablingCmd.Flags().StringVarP(&cablingOutput, "output", "o", "", "File to write the output to, stdout by default.")
+	cablingCmd.Flags().StringVarP(&cablingNamespace, "namespace", "n", "default", "Namespace of the resources without namespace.")
+	cablingCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intent
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/yndd/topology/internal/fabric"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	diffFromFiles []string
+	diffToFiles   []string
+	diffTemplate  string
+	diffOutput    string
+	diffNamespace string
+)
+
+// diffCmd compares the fabrics of 2 revisions of a template without cluster
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "compare the fabrics of 2 revisions of a template",
+	Long: "compare the fabrics of 2 revisions of a template and report the added and removed nodes, the relabeled nodes, " +
+		"the added, removed and moved links and the reindexed interfaces. The files of each revision hold the template " +
+		"and the templates, definitions and interface profiles it refers to.",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		from, err := newDiffFabric(ctx, diffFromFiles)
+		if err != nil {
+			return err
+		}
+		to, err := newDiffFabric(ctx, diffToFiles)
+		if err != nil {
+			return err
+		}
+		b, err := yaml.Marshal(fabric.Diff(from, to))
+		if err != nil {
+			return err
+		}
+		return writeOutput(diffOutput, b)
+	},
+}
+
+// newDiffFabric renders the fabric of the template of a revision
+func newDiffFabric(ctx context.Context, files []string) (fabric.Fabric, error) {
+	c, err := newOfflineClient(files, diffNamespace)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := getTemplate(ctx, c, diffTemplate)
+	if err != nil {
+		return nil, err
+	}
+	f, err := newOfflineFabric(ctx, c, tmpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot render template %s", tmpl.GetName())
+	}
+	return f, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringSliceVarP(&diffFromFiles, "from", "", []string{}, "Files with the old revision of the template and the resources it refers to.")
+	diffCmd.Flags().StringSliceVarP(&diffToFiles, "to", "", []string{}, "Files with the new revision of the template and the resources it refers to.")
+	diffCmd.Flags().StringVarP(&diffTemplate, "template", "t", "", "Name of the template to compare, required when the files have multiple templates.")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "File to write the output to, stdout by default.")
+	diffCmd.Flags().StringVarP(&diffNamespace, "namespace", "n", "default", "Namespace of the resources without namespace.")
+	diffCmd.MarkFlagRequired("from")
+	diffCmd.MarkFlagRequired("to")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intent
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/controllers/definition"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	generateFiles      []string
+	generateDefinition string
+	generateOutput     string
+	generateNamespace  string
+)
+
+// generateCmd prints the nodes and links the controller applies for definitions without cluster
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "generate the nodes and links of a definition",
+	Long: "generate the Node and Link manifests the controller applies for the templates of a definition. " +
+		"The files hold the definitions, their templates and the templates, definitions and interface profiles they refer to.",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		c, err := newOfflineClient(generateFiles, generateNamespace)
+		if err != nil {
+			return err
+		}
+		definitions, err := getDefinitions(ctx, c, generateDefinition)
+		if err != nil {
+			return err
+		}
+
+		b := &bytes.Buffer{}
+		for _, cr := range definitions {
+			nodes, links, err := definition.RenderResources(ctx, c, getOfflineLogger(), cr)
+			if err != nil {
+				return errors.Wrapf(err, "cannot render definition %s", cr.GetName())
+			}
+			for _, n := range nodes {
+				n.SetGroupVersionKind(topov1alpha1.NodeGroupVersionKind)
+				if err := writeManifest(b, n); err != nil {
+					return err
+				}
+			}
+			for _, l := range links {
+				l.SetGroupVersionKind(topov1alpha1.LinkGroupVersionKind)
+				if err := writeManifest(b, l); err != nil {
+					return err
+				}
+			}
+		}
+		return writeOutput(generateOutput, b.Bytes())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	generateCmd.Flags().StringSliceVarP(&generateFiles, "file", "f", []string{}, "Files with the definitions and the resources they refer to.")
+	generateCmd.Flags().StringVarP(&generateDefinition, "definition", "", "", "Name of the definition to generate, all definitions by default.")
+	generateCmd.Flags().StringVarP(&generateOutput, "output", "o", "", "File to write the manifests to, stdout by default.")
+	generateCmd.Flags().StringVarP(&generateNamespace, "namespace", "n", "default", "Namespace of the resources without namespace.")
+	generateCmd.MarkFlagRequired("file")
+}
+
+// getDefinitions returns the definition with the name or namespaced name, when no
+// name is given all definitions are returned
+func getDefinitions(ctx context.Context, c client.Client, name string) ([]*topov1alpha1.Definition, error) {
+	dl := &topov1alpha1.DefinitionList{}
+	if err := c.List(ctx, dl); err != nil {
+		return nil, err
+	}
+	definitions := make([]*topov1alpha1.Definition, 0, len(dl.Items))
+	for i := range dl.Items {
+		if name == "" || dl.Items[i].GetName() == name || dl.Items[i].GetNamespacedName() == name {
+			definitions = append(definitions, &dl.Items[i])
+		}
+	}
+	if len(definitions) == 0 {
+		if name != "" {
+			return nil, errors.Errorf("definition %s not found", name)
+		}
+		return nil, errors.New("no definitions found")
+	}
+	return definitions, nil
+}
+
+// writeManifest writes the resource as yaml document without the fields set by the
+// api server, the owner references have no uid since the owner is not created
+func writeManifest(b *bytes.Buffer, o client.Object) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return err
+	}
+	delete(u, "status")
+	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+	if refs, ok, _ := unstructured.NestedSlice(u, "metadata", "ownerReferences"); ok {
+		for _, ref := range refs {
+			if r, ok := ref.(map[string]interface{}); ok && r["uid"] == "" {
+				delete(r, "uid")
+			}
+		}
+		if err := unstructured.SetNestedSlice(u, refs, "metadata", "ownerReferences"); err != nil {
+			return err
+		}
+	}
+	y, err := yaml.Marshal(u)
+	if err != nil {
+		return err
+	}
+	b.WriteString("---\n")
+	b.Write(y)
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/fabric"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// loadObjects decodes the resources in the yaml files, resources without
+// namespace are put in the namespace. The defaults of the api server are set
+// on the templates since they are not read from the api server.
+func loadObjects(files []string, namespace string) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	objs := make([]client.Object, 0)
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read %s", file)
+		}
+		r := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(b)))
+		for {
+			doc, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot read %s", file)
+			}
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+			obj, _, err := decoder.Decode(doc, nil, nil)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot decode %s", file)
+			}
+			o, ok := obj.(client.Object)
+			if !ok {
+				return nil, errors.Errorf("unexpected object in %s", file)
+			}
+			if o.GetNamespace() == "" {
+				o.SetNamespace(namespace)
+			}
+			if t, ok := o.(*topov1alpha1.Template); ok && t.Spec.Properties.Fabric != nil {
+				t.Spec.Properties.Fabric.SetDefaults()
+			}
+			objs = append(objs, o)
+		}
+	}
+	return objs, nil
+}
+
+// newOfflineClient returns a client serving the resources of the yaml files such
+// that templates and definitions can be rendered without cluster
+func newOfflineClient(files []string, namespace string) (client.Client, error) {
+	objs, err := loadObjects(files, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), nil
+}
+
+// getTemplate returns the template with the name or namespaced name, when no name
+// is given the files should have a single template
+func getTemplate(ctx context.Context, c client.Client, name string) (*topov1alpha1.Template, error) {
+	tl := &topov1alpha1.TemplateList{}
+	if err := c.List(ctx, tl); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		if len(tl.Items) != 1 {
+			return nil, errors.Errorf("found %d templates, select the template to render with --template", len(tl.Items))
+		}
+		return &tl.Items[0], nil
+	}
+	for i := range tl.Items {
+		if tl.Items[i].GetName() == name || tl.Items[i].GetNamespacedName() == name {
+			return &tl.Items[i], nil
+		}
+	}
+	return nil, errors.Errorf("template %s not found", name)
+}
+
+// newOfflineFabric renders the fabric of the template with the interface profiles of the files
+func newOfflineFabric(ctx context.Context, c client.Client, tmpl *topov1alpha1.Template) (fabric.Fabric, error) {
+	if tmpl.Spec.Properties.Fabric == nil {
+		return nil, errors.Errorf("template %s has no fabric", tmpl.GetName())
+	}
+	profiles, err := fabric.GetInterfaceProfiles(ctx, c, tmpl.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	return fabric.NewFabric(tmpl.GetNamespacedName(), tmpl.Spec.Properties.Fabric,
+		fabric.WithLogger(getOfflineLogger()),
+		fabric.WithClient(c),
+		fabric.WithInterfaceProfiles(profiles),
+	)
+}
+
+// getOfflineLogger returns a logger writing to stderr in debug mode, the output of the
+// offline commands is written to stdout
+func getOfflineLogger() logging.Logger {
+	if !debug {
+		return logging.NewNopLogger()
+	}
+	return logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(os.Stderr)).WithName("topo"))
+}
+
+// writeOutput writes the output to the file, or to stdout when no file is given
+func writeOutput(file string, b []byte) error {
+	if file == "" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(file, b, 0644)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package intent
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatDOT          = "dot"
+	formatJSON         = "json"
+	formatContainerlab = "containerlab"
+)
+
+var (
+	renderFiles     []string
+	renderTemplate  string
+	renderFormat    string
+	renderOutput    string
+	renderNamespace string
+)
+
+// renderCmd renders the fabric of a template without cluster
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "render the fabric of a template",
+	Long: "render the fabric of a template as Graphviz DOT, node-link JSON graph or containerlab topology. " +
+		"The files hold the template and the templates, definitions and interface profiles it refers to.",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		c, err := newOfflineClient(renderFiles, renderNamespace)
+		if err != nil {
+			return err
+		}
+		tmpl, err := getTemplate(ctx, c, renderTemplate)
+		if err != nil {
+			return err
+		}
+		f, err := newOfflineFabric(ctx, c, tmpl)
+		if err != nil {
+			return errors.Wrapf(err, "cannot render template %s", tmpl.GetName())
+		}
+
+		var b []byte
+		switch renderFormat {
+		case formatDOT:
+			b, err = f.ExportDOT()
+		case formatJSON:
+			b, err = f.ExportJSON()
+		case formatContainerlab:
+			b, err = f.ExportContainerlab()
+		default:
+			return errors.Errorf("unsupported format %s, supported formats are %s, %s and %s", renderFormat, formatDOT, formatJSON, formatContainerlab)
+		}
+		if err != nil {
+			return err
+		}
+		return writeOutput(renderOutput, b)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().StringSliceVarP(&renderFiles, "file", "f", []string{}, "Files with the template and the resources it refers to.")
+	renderCmd.Flags().StringVarP(&renderTemplate, "template", "t", "", "Name of the template to render, required when the files have multiple templates.")
+	renderCmd.Flags().StringVarP(&renderFormat, "format", "", formatDOT, "Output format: dot, json or containerlab.")
+	renderCmd.Flags().StringVarP(&renderOutput, "output", "o", "", "File to write the output to, stdout by default.")
+	renderCmd.Flags().StringVarP(&renderNamespace, "namespace", "n", "default", "Namespace of the resources without namespace.")
+	renderCmd.MarkFlagRequired("file")
+}
@@ -36,7 +36,10 @@ import (
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	"github.com/yndd/ndd-runtime/pkg/ratelimiter"
 
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
 	"github.com/yndd/topology/internal/controllers"
+	"github.com/yndd/topology/internal/controllers/definition"
 
 	"github.com/yndd/ndd-runtime/pkg/shared"
 )
@@ -51,6 +54,8 @@ var (
 	podname              string
 	grpcServerAddress    string
 	grpcQueryAddress     string
+	enableWebhooks       bool
+	vendorTypes          []string
 )
 
 // startCmd represents the start command for the network device driver
@@ -97,6 +102,11 @@ var startCmd = &cobra.Command{
 			}
 		*/
 
+		// discovered targets of these vendor types are added to the topology
+		for _, vt := range vendorTypes {
+			definition.RegisterVendorTypes(targetv1.VendorType(vt))
+		}
+
 		// initialize controllers
 		if err := controllers.Setup(mgr, &shared.NddControllerOptions{
 			Logger:    logger,
@@ -110,6 +120,13 @@ var startCmd = &cobra.Command{
 			return errors.Wrap(err, "Cannot add nddo controllers to manager")
 		}
 
+		// initialize the validating webhooks
+		if enableWebhooks {
+			if err := setupWebhooks(mgr); err != nil {
+				return errors.Wrap(err, "Cannot add webhooks to manager")
+			}
+		}
+
 		// +kubebuilder:scaffold:builder
 
 		if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
@@ -139,6 +156,22 @@ func init() {
 	startCmd.Flags().StringVarP(&podname, "podname", "", os.Getenv("POD_NAME"), "Name from the pod")
 	startCmd.Flags().StringVarP(&grpcServerAddress, "grpc-server-address", "s", "", "The address of the grpc server binds to.")
 	startCmd.Flags().StringVarP(&grpcQueryAddress, "grpc-query-address", "", "", "Validation query address.")
+	startCmd.Flags().BoolVarP(&enableWebhooks, "enable-webhooks", "", false, "Enable the validating webhooks, requires the webhook certificates.")
+	startCmd.Flags().StringSliceVarP(&vendorTypes, "vendor-types", "", []string{}, "Additional vendor types of discovered targets which are added to the topology.")
+}
+
+func setupWebhooks(mgr ctrl.Manager) error {
+	for _, setup := range []func(ctrl.Manager) error{
+		(&topov1alpha1.Template{}).SetupWebhookWithManager,
+		(&topov1alpha1.Definition{}).SetupWebhookWithManager,
+		(&topov1alpha1.Link{}).SetupWebhookWithManager,
+		(&topov1alpha1.Node{}).SetupWebhookWithManager,
+	} {
+		if err := setup(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func nddCtlrOptions(c int) controller.Options {
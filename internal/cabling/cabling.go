@@ -0,0 +1,197 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cabling
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	targetv1 "github.com/yndd/target/apis/target/v1"
+)
+
+// Plan is the cabling plan of a fabric, it holds the cables to install and the bill
+// of materials of the devices and ports they connect
+type Plan struct {
+	Cables []*Cable
+	// devices per node name
+	devices map[string]*device
+}
+
+// Cable is a physical link between the ports of 2 nodes
+type Cable struct {
+	NodeA string
+	PortA string
+	NodeB string
+	PortB string
+	// pod of the leaf or spine the cable connects, 0 when not in a pod
+	Pod uint32
+	// superspine plane the cable connects to, 0 when not connected to a plane
+	Plane uint32
+}
+
+// BOMEntry is a line of the bill of materials per vendor type and platform
+type BOMEntry struct {
+	VendorType targetv1.VendorType
+	Platform   string
+	// number of devices
+	Devices uint32
+	// number of cabled ports, every port requires an optic
+	Ports uint32
+}
+
+type device struct {
+	vendorType targetv1.VendorType
+	platform   string
+	ports      uint32
+}
+
+// NewPlan returns an empty cabling plan
+func NewPlan() *Plan {
+	return &Plan{
+		Cables:  make([]*Cable, 0),
+		devices: map[string]*device{},
+	}
+}
+
+// AddNode adds a device to the bill of materials
+func (p *Plan) AddNode(name string, vendorType targetv1.VendorType, platform string) {
+	if _, ok := p.devices[name]; ok {
+		return
+	}
+	p.devices[name] = &device{vendorType: vendorType, platform: platform}
+}
+
+// AddCable adds a cable between the ports of 2 nodes, the ports are counted on the
+// nodes which are part of the plan
+func (p *Plan) AddCable(c *Cable) {
+	p.Cables = append(p.Cables, c)
+	for _, name := range []string{c.NodeA, c.NodeB} {
+		if d, ok := p.devices[name]; ok {
+			d.ports++
+		}
+	}
+}
+
+// IsEmpty returns true when the plan has no cables
+func (p *Plan) IsEmpty() bool {
+	return len(p.Cables) == 0
+}
+
+// GetBOM returns the bill of materials sorted by vendor type and platform
+func (p *Plan) GetBOM() []*BOMEntry {
+	type key struct {
+		vendorType targetv1.VendorType
+		platform   string
+	}
+	entries := map[key]*BOMEntry{}
+	for _, d := range p.devices {
+		k := key{vendorType: d.vendorType, platform: d.platform}
+		e, ok := entries[k]
+		if !ok {
+			e = &BOMEntry{VendorType: d.vendorType, Platform: d.platform}
+			entries[k] = e
+		}
+		e.Devices++
+		e.Ports += d.ports
+	}
+	bom := make([]*BOMEntry, 0, len(entries))
+	for _, e := range entries {
+		bom = append(bom, e)
+	}
+	sort.Slice(bom, func(i, j int) bool {
+		if bom[i].VendorType != bom[j].VendorType {
+			return bom[i].VendorType < bom[j].VendorType
+		}
+		return bom[i].Platform < bom[j].Platform
+	})
+	return bom
+}
+
+func (p *Plan) getCablingTable() [][]string {
+	rows := [][]string{{"A-node", "A-port", "B-node", "B-port", "pod", "plane"}}
+	for _, c := range p.Cables {
+		rows = append(rows, []string{c.NodeA, c.PortA, c.NodeB, c.PortB, formatIndex(c.Pod), formatIndex(c.Plane)})
+	}
+	return rows
+}
+
+func (p *Plan) getBOMTable() [][]string {
+	rows := [][]string{{"vendorType", "platform", "devices", "ports"}}
+	for _, e := range p.GetBOM() {
+		rows = append(rows, []string{string(e.VendorType), e.Platform,
+			strconv.Itoa(int(e.Devices)), strconv.Itoa(int(e.Ports))})
+	}
+	return rows
+}
+
+// CablingCSV returns the cabling sheet as CSV
+func (p *Plan) CablingCSV() ([]byte, error) {
+	return marshalCSV(p.getCablingTable())
+}
+
+// CablingMarkdown returns the cabling sheet as Markdown table
+func (p *Plan) CablingMarkdown() []byte {
+	return marshalMarkdown(p.getCablingTable())
+}
+
+// BOMCSV returns the bill of materials as CSV
+func (p *Plan) BOMCSV() ([]byte, error) {
+	return marshalCSV(p.getBOMTable())
+}
+
+// BOMMarkdown returns the bill of materials as Markdown table
+func (p *Plan) BOMMarkdown() []byte {
+	return marshalMarkdown(p.getBOMTable())
+}
+
+// formatIndex returns an empty string for an unset index
+func formatIndex(idx uint32) string {
+	if idx == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(idx))
+}
+
+func marshalCSV(rows [][]string) ([]byte, error) {
+	b := &bytes.Buffer{}
+	w := csv.NewWriter(b)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// marshalMarkdown returns the rows as Markdown table, the first row is the header
+func marshalMarkdown(rows [][]string) []byte {
+	b := &bytes.Buffer{}
+	escaper := strings.NewReplacer("|", "\\|")
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = escaper.Replace(cell)
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+		if i == 0 {
+			fmt.Fprintf(b, "|%s\n", strings.Repeat(" --- |", len(row)))
+		}
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cabling
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	targetv1 "github.com/yndd/target/apis/target/v1"
+)
+
+func TestGetBOM(t *testing.T) {
+	type node struct {
+		name       string
+		vendorType targetv1.VendorType
+		platform   string
+	}
+	cases := map[string]struct {
+		nodes  []node
+		cables []*Cable
+		want   []*BOMEntry
+	}{
+		"Empty": {
+			want: []*BOMEntry{},
+		},
+		"PortsPerPlatform": {
+			nodes: []node{
+				{name: "leaf1", vendorType: targetv1.VendorTypeNokiaSRL, platform: "IXR-D2"},
+				{name: "leaf2", vendorType: targetv1.VendorTypeNokiaSRL, platform: "IXR-D2"},
+				{name: "spine1", vendorType: targetv1.VendorTypeNokiaSRL, platform: "IXR-D3"},
+			},
+			cables: []*Cable{
+				{NodeA: "spine1", PortA: "int-1/1", NodeB: "leaf1", PortB: "int-1/49"},
+				{NodeA: "spine1", PortA: "int-1/2", NodeB: "leaf1", PortB: "int-1/50"},
+				{NodeA: "spine1", PortA: "int-1/3", NodeB: "leaf2", PortB: "int-1/49"},
+			},
+			want: []*BOMEntry{
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D2", Devices: 2, Ports: 3},
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D3", Devices: 1, Ports: 3},
+			},
+		},
+		"DeviceWithoutCables": {
+			nodes: []node{
+				{name: "leaf1", vendorType: targetv1.VendorTypeNokiaSRL, platform: "IXR-D2"},
+				{name: "dcgw1", vendorType: targetv1.VendorTypeNokiaSROS, platform: "SR-1"},
+			},
+			want: []*BOMEntry{
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D2", Devices: 1, Ports: 0},
+				{VendorType: targetv1.VendorTypeNokiaSROS, Platform: "SR-1", Devices: 1, Ports: 0},
+			},
+		},
+		"CableToNodeOutsidePlan": {
+			nodes: []node{
+				{name: "leaf1", vendorType: targetv1.VendorTypeNokiaSRL, platform: "IXR-D2"},
+			},
+			cables: []*Cable{
+				{NodeA: "spine1", PortA: "int-1/1", NodeB: "leaf1", PortB: "int-1/49"},
+			},
+			want: []*BOMEntry{
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D2", Devices: 1, Ports: 1},
+			},
+		},
+		"NodeAddedTwice": {
+			nodes: []node{
+				{name: "leaf1", vendorType: targetv1.VendorTypeNokiaSRL, platform: "IXR-D2"},
+				{name: "leaf1", vendorType: targetv1.VendorTypeNokiaSRL, platform: "IXR-D2"},
+			},
+			want: []*BOMEntry{
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D2", Devices: 1, Ports: 0},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewPlan()
+			for _, n := range tc.nodes {
+				p.AddNode(n.name, n.vendorType, n.platform)
+			}
+			for _, c := range tc.cables {
+				p.AddCable(c)
+			}
+			got := p.GetBOM()
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("GetBOM(): want %s, got %s", formatBOM(tc.want), formatBOM(got))
+			}
+		})
+	}
+}
+
+func formatBOM(bom []*BOMEntry) string {
+	s := ""
+	for _, e := range bom {
+		s += fmt.Sprintf("%+v ", *e)
+	}
+	return s
+}
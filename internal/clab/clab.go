@@ -0,0 +1,213 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clab
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// Topology is a containerlab topology file
+type Topology struct {
+	Name     string              `json:"name"`
+	Topology *TopologyDefinition `json:"topology"`
+	// vendor type per node name
+	vendorTypes map[string]targetv1.VendorType
+}
+
+type TopologyDefinition struct {
+	Nodes map[string]*Node `json:"nodes"`
+	Links []*Link          `json:"links,omitempty"`
+}
+
+type Node struct {
+	Kind   string            `json:"kind"`
+	Type   string            `json:"type,omitempty"`
+	Image  string            `json:"image,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type Link struct {
+	Endpoints []string `json:"endpoints"`
+}
+
+// kind defines how nodes of a vendor type are run in containerlab
+type kind struct {
+	kind  string
+	image string
+	// getType returns the containerlab type of the platform
+	getType func(platform string) string
+	// getInterfaceName returns the containerlab name of the interface
+	getInterfaceName func(slot, port, subPort string) string
+}
+
+var (
+	kinds = map[targetv1.VendorType]*kind{
+		targetv1.VendorTypeNokiaSRL: {
+			kind:    "srl",
+			image:   "ghcr.io/nokia/srlinux",
+			getType: getSrlType,
+			getInterfaceName: func(slot, port, subPort string) string {
+				if subPort != "" {
+					return "e" + slot + "-" + port + "-" + subPort
+				}
+				return "e" + slot + "-" + port
+			},
+		},
+		targetv1.VendorTypeNokiaSROS: {
+			kind:    "vr-sros",
+			image:   "vrnetlab/vr-sros",
+			getType: getSrosType,
+			getInterfaceName: func(slot, port, subPort string) string {
+				return "eth" + port
+			},
+		},
+	}
+	// nodes of other vendor types run as linux containers
+	defaultKind = &kind{
+		kind:    "linux",
+		image:   "alpine:latest",
+		getType: func(platform string) string { return "" },
+		getInterfaceName: func(slot, port, subPort string) string {
+			return "eth" + port
+		},
+	}
+	// int-<slot>/<port> or int-<slot>/<port>/<subport>
+	interfaceNameRegex = regexp.MustCompile(`^int-(\d+)/(\d+)(?:/(\d+))?$`)
+)
+
+func getKind(vendorType targetv1.VendorType) *kind {
+	if k, ok := kinds[vendorType]; ok {
+		return k
+	}
+	return defaultKind
+}
+
+// getSrlType returns the srl type of the platform, e.g. 7220 IXR-D2 is ixrd2
+func getSrlType(platform string) string {
+	fields := strings.Fields(platform)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.ReplaceAll(fields[len(fields)-1], "-", ""))
+}
+
+// getSrosType returns the sros type of the platform, e.g. 7750 SR1 is sr-1
+func getSrosType(platform string) string {
+	fields := strings.Fields(platform)
+	if len(fields) == 0 {
+		return ""
+	}
+	t := strings.ToLower(fields[len(fields)-1])
+	if strings.HasPrefix(t, "sr") && !strings.HasPrefix(t, "sr-") {
+		t = "sr-" + strings.TrimPrefix(t, "sr")
+	}
+	return t
+}
+
+// NewTopology returns an empty containerlab topology, the name is used as lab name
+func NewTopology(name string) *Topology {
+	return &Topology{
+		Name: strings.NewReplacer(".", "-", "/", "-").Replace(name),
+		Topology: &TopologyDefinition{
+			Nodes: map[string]*Node{},
+			Links: make([]*Link, 0),
+		},
+		vendorTypes: map[string]targetv1.VendorType{},
+	}
+}
+
+// AddNode adds a node, the kind and type of the node are derived from the vendor type
+// and platform
+func (t *Topology) AddNode(name string, vendorType targetv1.VendorType, platform string, position topov1alpha1.Position) {
+	labels := map[string]string{}
+	if position != "" {
+		labels["position"] = string(position)
+	}
+	k := getKind(vendorType)
+	t.Topology.Nodes[name] = &Node{
+		Kind:   k.kind,
+		Type:   k.getType(platform),
+		Image:  k.image,
+		Labels: labels,
+	}
+	t.vendorTypes[name] = vendorType
+}
+
+// AddLink adds a link between the interfaces of 2 nodes, the interface names are
+// converted to the names containerlab uses for the kind of the node. Links to nodes
+// which are not part of the lab are skipped.
+func (t *Topology) AddLink(nodeA, ifNameA, nodeB, ifNameB string) {
+	vendorTypeA, ok := t.vendorTypes[nodeA]
+	if !ok {
+		return
+	}
+	vendorTypeB, ok := t.vendorTypes[nodeB]
+	if !ok {
+		return
+	}
+	t.Topology.Links = append(t.Topology.Links, &Link{
+		Endpoints: []string{
+			nodeA + ":" + getInterfaceName(vendorTypeA, ifNameA),
+			nodeB + ":" + getInterfaceName(vendorTypeB, ifNameB),
+		},
+	})
+}
+
+// Marshal returns the containerlab topology file
+func (t *Topology) Marshal() ([]byte, error) {
+	sort.Slice(t.Topology.Links, func(i, j int) bool {
+		return strings.Join(t.Topology.Links[i].Endpoints, " ") < strings.Join(t.Topology.Links[j].Endpoints, " ")
+	})
+	return yaml.Marshal(t)
+}
+
+// Render returns the containerlab topology of the nodes and the physical links
+// between them, logical lag links are not part of the lab.
+func Render(name string, nodes []*topov1alpha1.Node, links []*topov1alpha1.Link) ([]byte, error) {
+	t := NewTopology(name)
+	for _, n := range nodes {
+		if n.Spec.Properties == nil {
+			t.AddNode(n.GetNodeName(), "", "", "")
+			continue
+		}
+		t.AddNode(n.GetNodeName(), n.Spec.Properties.VendorType, n.Spec.Properties.Platform, n.Spec.Properties.Position)
+	}
+	for _, l := range links {
+		if l.Spec.Properties == nil || l.GetLag() || len(l.Spec.Properties.Endpoints) != 2 {
+			continue
+		}
+		epA := l.Spec.Properties.Endpoints[0]
+		epB := l.Spec.Properties.Endpoints[1]
+		t.AddLink(epA.NodeName, epA.InterfaceName, epB.NodeName, epB.InterfaceName)
+	}
+	return t.Marshal()
+}
+
+// getInterfaceName returns the containerlab name of the interface of a node of the vendor type
+func getInterfaceName(vendorType targetv1.VendorType, ifName string) string {
+	m := interfaceNameRegex.FindStringSubmatch(ifName)
+	if m == nil {
+		return ifName
+	}
+	return getKind(vendorType).getInterfaceName(m[1], m[2], m[3])
+}
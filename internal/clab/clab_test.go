@@ -0,0 +1,65 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clab
+
+import (
+	"testing"
+
+	targetv1 "github.com/yndd/target/apis/target/v1"
+)
+
+func TestGetInterfaceName(t *testing.T) {
+	cases := map[string]struct {
+		vendorType targetv1.VendorType
+		ifName     string
+		want       string
+	}{
+		"SRL": {
+			vendorType: targetv1.VendorTypeNokiaSRL,
+			ifName:     "int-1/49",
+			want:       "e1-49",
+		},
+		"SRLBreakout": {
+			vendorType: targetv1.VendorTypeNokiaSRL,
+			ifName:     "int-1/3/2",
+			want:       "e1-3-2",
+		},
+		"SROS": {
+			vendorType: targetv1.VendorTypeNokiaSROS,
+			ifName:     "int-1/5",
+			want:       "eth5",
+		},
+		"UnknownVendorType": {
+			vendorType: targetv1.VendorType("other"),
+			ifName:     "int-2/7",
+			want:       "eth7",
+		},
+		"NotAFabricInterface": {
+			vendorType: targetv1.VendorTypeNokiaSRL,
+			ifName:     "ethernet-1/1",
+			want:       "ethernet-1/1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := getInterfaceName(tc.vendorType, tc.ifName); got != tc.want {
+				t.Errorf("getInterfaceName(%s, %s): want %s, got %s", tc.vendorType, tc.ifName, tc.want, got)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/yndd/topology/internal/controllers/definition"
 	"github.com/yndd/topology/internal/controllers/link"
 	"github.com/yndd/topology/internal/controllers/node"
+	"github.com/yndd/topology/internal/controllers/template"
 	"github.com/yndd/topology/internal/controllers/topology"
 )
 
@@ -33,6 +34,7 @@ func Setup(mgr ctrl.Manager, nddcopts *shared.NddControllerOptions) error {
 		topology.Setup,
 		link.Setup,
 		node.Setup,
+		template.Setup,
 	} {
 		if err := setup(mgr, nddcopts); err != nil {
 			return err
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	"github.com/yndd/ndd-runtime/pkg/resource"
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// errors
+	errUnbindNode     = "cannot unbind node"
+	errUpdateBindNode = "cannot update binding condition of node"
+)
+
+// nodeBinder binds discovered targets to the planned fabric nodes. The serial number
+// and mac address of a planned node are set on the node by the operator, a target
+// with the same serial number or mac address is bound to the node instead of being
+// rendered as a separate node.
+type nodeBinder struct {
+	// planned nodes per serial number and mac address
+	serialNumbers map[string]*topov1alpha1.Node
+	macAddresses  map[string]*topov1alpha1.Node
+	bindings      map[string]*topov1alpha1.NodeBinding
+	// existing planned nodes, their target reference and condition reflect the
+	// bindings of the previous reconciliation
+	existing map[string]*topov1alpha1.Node
+}
+
+// newNodeBinder returns a binder for the fabric nodes rendered so far, the identity of
+// the nodes is taken from the existing nodes since the templates do not define it
+func (r *applogic) newNodeBinder(ctx context.Context, cr *topov1alpha1.Definition, res *resources) (*nodeBinder, error) {
+	b := &nodeBinder{
+		serialNumbers: map[string]*topov1alpha1.Node{},
+		macAddresses:  map[string]*topov1alpha1.Node{},
+		bindings:      map[string]*topov1alpha1.NodeBinding{},
+		existing:      map[string]*topov1alpha1.Node{},
+	}
+	existing, err := r.getOwnedNodes(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	for name, n := range res.nodes {
+		en, ok := existing[name]
+		if !ok || en.Spec.Properties == nil {
+			continue
+		}
+		b.existing[name] = en
+		// keep the identity such that the rendered node does not differ from the existing node
+		n.Spec.Properties.SerialNumber = en.Spec.Properties.SerialNumber
+		n.Spec.Properties.MacAddress = en.Spec.Properties.MacAddress
+		if sn := n.Spec.Properties.SerialNumber; sn != "" {
+			b.serialNumbers[normalizeSerialNumber(sn)] = n
+		}
+		if mac := n.Spec.Properties.MacAddress; mac != "" {
+			b.macAddresses[normalizeMacAddress(mac)] = n
+		}
+	}
+	return b, nil
+}
+
+// bind binds the target to the planned node with the same serial number or mac address
+// and returns the name of the node, it returns false if no planned node matches the target
+func (b *nodeBinder) bind(t *targetv1.Target) (string, bool) {
+	var n *topov1alpha1.Node
+	if sn := t.GetDiscoveryInfo().SerialNumber; sn != "" {
+		n = b.serialNumbers[normalizeSerialNumber(sn)]
+	}
+	if mac := t.GetDiscoveryInfo().MacAddress; n == nil && mac != "" {
+		n = b.macAddresses[normalizeMacAddress(mac)]
+	}
+	if n == nil {
+		return "", false
+	}
+	// a planned node is bound to a single target
+	if _, ok := b.bindings[n.GetName()]; ok {
+		return "", false
+	}
+	n.Spec.TargetReference = &nddv1.Reference{Name: t.GetName()}
+	b.bindings[n.GetName()] = &topov1alpha1.NodeBinding{
+		Node:       n.GetName(),
+		Target:     t.GetName(),
+		Mismatches: getMismatches(n, t),
+	}
+	return n.GetName(), true
+}
+
+// updateNodes records the bindings on the planned nodes. The target reference of a node
+// which is no longer bound is cleared, since applying the rendered node does not remove
+// it. The health of a node reports the mismatches with its target.
+func (r *applogic) updateNodes(ctx context.Context, b *nodeBinder) error {
+	for name, n := range b.existing {
+		nb, bound := b.bindings[name]
+		if !bound && n.Spec.TargetReference != nil {
+			r.log.Debug("unbind node", "name", name, "target", n.Spec.TargetReference.Name)
+			patch := client.MergeFrom(n.DeepCopy())
+			n.Spec.TargetReference = nil
+			if err := r.client.Patch(ctx, n, patch); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, errUnbindNode)
+			}
+		}
+		c := topov1alpha1.Healthy()
+		if bound && len(nb.Mismatches) > 0 {
+			c = topov1alpha1.Unhealthy(topov1alpha1.ConditionReasonTargetMismatch).WithMessage(
+				fmt.Sprintf("target %s: %s", nb.Target, strings.Join(nb.Mismatches, ", ")))
+		}
+		current := n.GetCondition(topov1alpha1.ConditionKindHealthy)
+		// nodes which were never bound have no health condition
+		if (!bound && current.Status == corev1.ConditionUnknown) || current.Equal(c) {
+			continue
+		}
+		patch := client.MergeFromWithOptions(n.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		n.SetConditions(c)
+		if err := r.client.Status().Patch(ctx, n, patch); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errUpdateBindNode)
+		}
+	}
+	return nil
+}
+
+// getBindings returns the bindings sorted by node name
+func (b *nodeBinder) getBindings() []*topov1alpha1.NodeBinding {
+	bindings := make([]*topov1alpha1.NodeBinding, 0, len(b.bindings))
+	for _, nb := range b.bindings {
+		bindings = append(bindings, nb)
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Node < bindings[j].Node
+	})
+	return bindings
+}
+
+// getMismatches returns the differences in vendor type and platform between the planned
+// node and the discovered target, properties which are unknown on either side are skipped
+func getMismatches(n *topov1alpha1.Node, t *targetv1.Target) []string {
+	mismatches := make([]string, 0)
+	if planned, discovered := n.Spec.Properties.VendorType, getTargetVendorType(t); planned != "" && discovered != "" && planned != discovered {
+		mismatches = append(mismatches, fmt.Sprintf("vendorType planned %s discovered %s", planned, discovered))
+	}
+	if planned, discovered := n.Spec.Properties.Platform, t.GetDiscoveryInfo().Platform; planned != "" && discovered != "" && !strings.EqualFold(planned, discovered) {
+		mismatches = append(mismatches, fmt.Sprintf("platform planned %s discovered %s", planned, discovered))
+	}
+	return mismatches
+}
+
+func normalizeSerialNumber(sn string) string {
+	return strings.ToUpper(strings.TrimSpace(sn))
+}
+
+// normalizeMacAddress returns the mac address in lower case without separators
+func normalizeMacAddress(mac string) string {
+	return strings.NewReplacer(":", "", "-", "", ".", "").Replace(strings.ToLower(strings.TrimSpace(mac)))
+}
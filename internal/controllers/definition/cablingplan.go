@@ -0,0 +1,93 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	"github.com/yndd/ndd-runtime/pkg/meta"
+	"github.com/yndd/ndd-runtime/pkg/resource"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// keys of the cabling sheet and the bill of materials in the cabling plan ConfigMap
+	CablingCSVKey      = "cabling.csv"
+	CablingMarkdownKey = "cabling.md"
+	BOMCSVKey          = "bom.csv"
+	BOMMarkdownKey     = "bom.md"
+	// errors
+	errRenderCablingPlan = "cannot render cabling plan"
+	errApplyCablingPlan  = "cannot apply cabling plan"
+	errDeleteCablingPlan = "cannot delete cabling plan"
+)
+
+// getCablingPlanName returns the name of the ConfigMap of the cabling plan of the definition
+func getCablingPlanName(cr *topov1alpha1.Definition) string {
+	return strings.Join([]string{cr.GetName(), "cabling"}, ".")
+}
+
+// renderCablingPlan renders the cabling sheet and the bill of materials of the fabrics
+// of the templates as CSV and Markdown in a ConfigMap. The ConfigMap is deleted when the
+// definition no longer has cables, in plan mode nothing is applied.
+func (r *applogic) renderCablingPlan(ctx context.Context, cr *topov1alpha1.Definition, res *resources) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            getCablingPlanName(cr),
+			Namespace:       cr.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, topov1alpha1.DefinitionGroupVersionKind))},
+		},
+	}
+
+	if res.cablingPlan.IsEmpty() {
+		if !cr.GetPlan() && cr.Status.CablingPlanReference != nil {
+			if err := r.client.Delete(ctx, cm); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, errDeleteCablingPlan)
+			}
+			cr.Status.CablingPlanReference = nil
+		}
+		return nil
+	}
+
+	cablingCSV, err := res.cablingPlan.CablingCSV()
+	if err != nil {
+		return errors.Wrap(err, errRenderCablingPlan)
+	}
+	bomCSV, err := res.cablingPlan.BOMCSV()
+	if err != nil {
+		return errors.Wrap(err, errRenderCablingPlan)
+	}
+	cm.Data = map[string]string{
+		CablingCSVKey:      string(cablingCSV),
+		CablingMarkdownKey: string(res.cablingPlan.CablingMarkdown()),
+		BOMCSVKey:          string(bomCSV),
+		BOMMarkdownKey:     string(res.cablingPlan.BOMMarkdown()),
+	}
+	if cr.GetPlan() {
+		return nil
+	}
+	if err := r.client.Apply(ctx, cm); err != nil {
+		return errors.Wrap(err, errApplyCablingPlan)
+	}
+	cr.Status.CablingPlanReference = &nddv1.Reference{Name: cm.GetName()}
+	return nil
+}
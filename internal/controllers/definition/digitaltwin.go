@@ -0,0 +1,101 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	"github.com/yndd/ndd-runtime/pkg/meta"
+	"github.com/yndd/ndd-runtime/pkg/resource"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/clab"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DigitalTwinKey is the key of the containerlab topology in the digital twin ConfigMap
+	DigitalTwinKey = "topology.clab.yml"
+	// errors
+	errRenderDigitalTwin = "cannot render digital twin"
+	errApplyDigitalTwin  = "cannot apply digital twin"
+	errDeleteDigitalTwin = "cannot delete digital twin"
+)
+
+func (x *resources) addDigitalTwinNode(name string) {
+	x.digitalTwinNodes[name] = struct{}{}
+}
+
+func (x *resources) addDigitalTwinLink(name string) {
+	x.digitalTwinLinks[name] = struct{}{}
+}
+
+// getDigitalTwinName returns the name of the ConfigMap of the digital twin of the definition
+func getDigitalTwinName(cr *topov1alpha1.Definition) string {
+	return strings.Join([]string{cr.GetName(), "digitaltwin"}, ".")
+}
+
+// renderDigitalTwin renders the nodes and links of the templates and discovery rules
+// which are a digital twin as a containerlab topology in a ConfigMap. The ConfigMap is
+// deleted when the definition no longer has a digital twin, in plan mode nothing is applied.
+func (r *applogic) renderDigitalTwin(ctx context.Context, cr *topov1alpha1.Definition, res *resources) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            getDigitalTwinName(cr),
+			Namespace:       cr.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, topov1alpha1.DefinitionGroupVersionKind))},
+		},
+	}
+
+	if len(res.digitalTwinNodes) == 0 {
+		if !cr.GetPlan() && cr.Status.DigitalTwinReference != nil {
+			if err := r.client.Delete(ctx, cm); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, errDeleteDigitalTwin)
+			}
+			cr.Status.DigitalTwinReference = nil
+		}
+		return nil
+	}
+
+	nodes := make([]*topov1alpha1.Node, 0, len(res.digitalTwinNodes))
+	for name := range res.digitalTwinNodes {
+		nodes = append(nodes, res.nodes[name])
+	}
+	links := make([]*topov1alpha1.Link, 0, len(res.digitalTwinLinks))
+	for name := range res.digitalTwinLinks {
+		links = append(links, res.links[name])
+	}
+
+	b, err := clab.Render(cr.GetName(), nodes, links)
+	if err != nil {
+		return errors.Wrap(err, errRenderDigitalTwin)
+	}
+	cm.Data = map[string]string{
+		DigitalTwinKey: string(b),
+	}
+	if cr.GetPlan() {
+		return nil
+	}
+	if err := r.client.Apply(ctx, cm); err != nil {
+		return errors.Wrap(err, errApplyDigitalTwin)
+	}
+	cr.Status.DigitalTwinReference = &nddv1.Reference{Name: cm.GetName()}
+	return nil
+}
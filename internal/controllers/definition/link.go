@@ -42,17 +42,22 @@ func renderFabricLink(cr *topov1alpha1.Definition, link fabric.FabricLink) *topo
 		},
 		Spec: topov1alpha1.LinkSpec{
 			Properties: &topov1alpha1.LinkProperties{
-				Kind: topov1alpha1.LinkKindInfra,
+				Kind:      topov1alpha1.LinkKindInfra,
+				LagMember: link.GetLagMember(),
+				Lag:       link.GetLag(),
+				Lacp:      link.GetLag(),
 				Endpoints: []*topov1alpha1.Endpoints{
 					{
 						InterfaceName: link.GetEndpointA().IfName,
 						NodeName:      link.GetEndpointA().Node.GetNodeName(),
 						Kind:          topov1alpha1.EndpointKindInfra,
+						LagName:       link.GetEndpointA().LagName,
 					},
 					{
 						InterfaceName: link.GetEndpointB().IfName,
 						NodeName:      link.GetEndpointB().Node.GetNodeName(),
 						Kind:          topov1alpha1.EndpointKindInfra,
+						LagName:       link.GetEndpointB().LagName,
 					},
 				},
 			},
@@ -17,7 +17,7 @@ limitations under the License.
 package definition
 
 import (
-	"strconv"
+	"net"
 	"strings"
 
 	"github.com/yndd/ndd-runtime/pkg/meta"
@@ -28,15 +28,15 @@ import (
 )
 
 const (
-	LabelKeyTopologyPosition   = "topology.yndd.io/position"
-	LabelKeyTopologyNodeIndex  = "topology.yndd.io/NodeIndex"
-	LabelKeyTopologyPodIndex   = "topology.yndd.io/PodIndex"
-	LabelKeyTopologyPlatform   = "topology.yndd.io/Platform"
-	LabelKeyTopologyVendorType = "topology.yndd.io/VendorType"
-	LabelKeyOrganization       = "org.yndd.io/organization"
-	LabelKeyDeployment         = "org.yndd.io/deployment"
-	LabelKeyAvailabilityZone   = "org.yndd.io/availabilityzone"
-	LabelKeyTopology           = "org.yndd.io/topology"
+	LabelKeyTopologyPosition   = topov1alpha1.LabelKeyTopologyPosition
+	LabelKeyTopologyNodeIndex  = topov1alpha1.LabelKeyTopologyNodeIndex
+	LabelKeyTopologyPodIndex   = topov1alpha1.LabelKeyTopologyPodIndex
+	LabelKeyTopologyPlatform   = topov1alpha1.LabelKeyTopologyPlatform
+	LabelKeyTopologyVendorType = topov1alpha1.LabelKeyTopologyVendorType
+	LabelKeyOrganization       = topov1alpha1.LabelKeyOrganization
+	LabelKeyDeployment         = topov1alpha1.LabelKeyDeployment
+	LabelKeyAvailabilityZone   = topov1alpha1.LabelKeyAvailabilityZone
+	LabelKeyTopology           = topov1alpha1.LabelKeyTopology
 )
 
 func renderNode(drName string, cr *topov1alpha1.Definition, t *targetv1.Target) *topov1alpha1.Node { // nolint:interfacer,gocyclo
@@ -45,14 +45,22 @@ func renderNode(drName string, cr *topov1alpha1.Definition, t *targetv1.Target)
 			Name:      strings.Join([]string{cr.GetName(), t.GetName()}, "."),
 			Namespace: cr.Namespace,
 			Labels: map[string]string{
-				LabelKeyDiscoveryRule: drName,
+				LabelKeyDiscoveryRule:    drName,
+				LabelKeyOrganization:     cr.GetOrganization(),
+				LabelKeyDeployment:       cr.GetDeployment(),
+				LabelKeyAvailabilityZone: cr.GetAvailabilityZone(),
+				LabelKeyTopology:         cr.GetTopologyName(),
 			},
 			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, topov1alpha1.DefinitionGroupVersionKind))},
 		},
 		Spec: topov1alpha1.NodeSpec{
 			Properties: &topov1alpha1.NodeProperties{
-				//VendorType: t.GetDiscoveryInfo().VendorType,
-				Platform: t.GetDiscoveryInfo().Platform,
+				VendorType:        getTargetVendorType(t),
+				Platform:          t.GetDiscoveryInfo().Platform,
+				MacAddress:        t.GetDiscoveryInfo().MacAddress,
+				SerialNumber:      t.GetDiscoveryInfo().SerialNumber,
+				ExpectedSWVersion: t.GetDiscoveryInfo().SwVersion,
+				MgmtIPAddress:     getTargetMgmtIPAddress(t),
 				//Index:
 				//Position:
 				// Tags://
@@ -61,6 +69,29 @@ func renderNode(drName string, cr *topov1alpha1.Definition, t *targetv1.Target)
 	}
 }
 
+// getTargetVendorType returns the discovered vendor type of the target, falling back
+// to the vendor type of its spec
+func getTargetVendorType(t *targetv1.Target) targetv1.VendorType {
+	if vt := t.GetDiscoveryInfo().VendorType; vt != "" && vt != targetv1.VendorTypeUnknown {
+		return vt
+	}
+	if t.Spec.Properties != nil {
+		return t.Spec.Properties.VendorType
+	}
+	return ""
+}
+
+// getTargetMgmtIPAddress returns the ip address the target is reached on, without port
+func getTargetMgmtIPAddress(t *targetv1.Target) string {
+	if t.Spec.Properties == nil || t.Spec.Properties.Config == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(t.Spec.Properties.Config.Address); err == nil {
+		return host
+	}
+	return t.Spec.Properties.Config.Address
+}
+
 type FabricNodeInfo struct {
 	Position      topov1alpha1.Position
 	NodeIndex     uint32 // relative number within the position, pod
@@ -70,19 +101,11 @@ type FabricNodeInfo struct {
 }
 
 func renderFabricNode(cr *topov1alpha1.Definition, nodeInfo fabric.FabricNode) *topov1alpha1.Node { // nolint:interfacer,gocyclo
-	labels := map[string]string{
-		LabelKeyTopologyPosition:   string(nodeInfo.GetPosition()),
-		LabelKeyTopologyNodeIndex:  strconv.Itoa(int(nodeInfo.GetNodeIndex())),
-		LabelKeyTopologyVendorType: string(nodeInfo.GetVendorType()),
-		LabelKeyTopologyPlatform:   nodeInfo.GetPlatform(),
-		LabelKeyOrganization:       cr.GetOrganization(),
-		LabelKeyDeployment:         cr.GetDeployment(),
-		LabelKeyAvailabilityZone:   cr.GetAvailabilityZone(),
-		LabelKeyTopology:           cr.GetTopologyName(),
-	}
-	if nodeInfo.GetPosition() != topov1alpha1.PositionSuperspine {
-		labels[LabelKeyTopologyPodIndex] = strconv.Itoa(int(nodeInfo.GetPodIndex()))
-	}
+	labels := nodeInfo.GetLabels()
+	labels[LabelKeyOrganization] = cr.GetOrganization()
+	labels[LabelKeyDeployment] = cr.GetDeployment()
+	labels[LabelKeyAvailabilityZone] = cr.GetAvailabilityZone()
+	labels[LabelKeyTopology] = cr.GetTopologyName()
 
 	/*
 		oda := nddv1.OdaInfo{
@@ -0,0 +1,229 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"sort"
+
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/cabling"
+	"github.com/yndd/topology/internal/fabric"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resources holds the nodes and links rendered for a definition, indexed by name
+type resources struct {
+	nodes map[string]*topov1alpha1.Node
+	links map[string]*topov1alpha1.Link
+	// vendor types and platforms used in the templates, in order of use
+	vendorTypeInfo []*topov1alpha1.NodeProperties
+	// names of the nodes and links which are part of the digital twin
+	digitalTwinNodes map[string]struct{}
+	digitalTwinLinks map[string]struct{}
+	// cables and devices of the fabrics of the templates
+	cablingPlan *cabling.Plan
+}
+
+func newResources() *resources {
+	return &resources{
+		nodes:            map[string]*topov1alpha1.Node{},
+		links:            map[string]*topov1alpha1.Link{},
+		vendorTypeInfo:   make([]*topov1alpha1.NodeProperties, 0),
+		digitalTwinNodes: map[string]struct{}{},
+		digitalTwinLinks: map[string]struct{}{},
+		cablingPlan:      cabling.NewPlan(),
+	}
+}
+
+func (x *resources) addNode(n *topov1alpha1.Node) {
+	x.nodes[n.GetName()] = n
+}
+
+func (x *resources) addLink(l *topov1alpha1.Link) {
+	x.links[l.GetName()] = l
+}
+
+// applyResources creates or updates the rendered nodes and links
+func (r *applogic) applyResources(ctx context.Context, res *resources) error {
+	for _, n := range res.nodes {
+		if err := r.client.Apply(ctx, n); err != nil {
+			return err
+		}
+	}
+	for _, l := range res.links {
+		if err := r.client.Apply(ctx, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getOwnedListOptions selects the resources rendered for the topology of the definition,
+// the owner reference is checked on the selected resources
+func getOwnedListOptions(cr *topov1alpha1.Definition) []client.ListOption {
+	return []client.ListOption{
+		client.InNamespace(cr.GetNamespace()),
+		client.MatchingLabels{LabelKeyTopology: cr.GetTopologyName()},
+	}
+}
+
+// getOwnedNodes returns the nodes of the topology of the definition controlled by it
+func (r *applogic) getOwnedNodes(ctx context.Context, cr *topov1alpha1.Definition) (map[string]*topov1alpha1.Node, error) {
+	nl := &topov1alpha1.NodeList{}
+	if err := r.client.List(ctx, nl, getOwnedListOptions(cr)...); err != nil {
+		return nil, err
+	}
+	nodes := map[string]*topov1alpha1.Node{}
+	for i := range nl.Items {
+		if metav1.IsControlledBy(&nl.Items[i], cr) {
+			nodes[nl.Items[i].GetName()] = &nl.Items[i]
+		}
+	}
+	return nodes, nil
+}
+
+// getOwnedLinks returns the links of the topology of the definition controlled by it
+func (r *applogic) getOwnedLinks(ctx context.Context, cr *topov1alpha1.Definition) (map[string]*topov1alpha1.Link, error) {
+	ll := &topov1alpha1.LinkList{}
+	if err := r.client.List(ctx, ll, getOwnedListOptions(cr)...); err != nil {
+		return nil, err
+	}
+	links := map[string]*topov1alpha1.Link{}
+	for i := range ll.Items {
+		if metav1.IsControlledBy(&ll.Items[i], cr) {
+			links[ll.Items[i].GetName()] = &ll.Items[i]
+		}
+	}
+	return links, nil
+}
+
+// planResources compares the rendered nodes and links with the nodes and links
+// owned by the definition and returns the changes an apply would make
+func (r *applogic) planResources(ctx context.Context, cr *topov1alpha1.Definition, res *resources) (*topov1alpha1.DefinitionPlan, error) {
+	ownedNodes, err := r.getOwnedNodes(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	ownedLinks, err := r.getOwnedLinks(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeChanges := &topov1alpha1.DefinitionPlanChanges{}
+	for name, n := range res.nodes {
+		on, ok := ownedNodes[name]
+		switch {
+		case !ok:
+			nodeChanges.Create = append(nodeChanges.Create, name)
+		case !equality.Semantic.DeepEqual(n.Spec.Properties, on.Spec.Properties) ||
+			!labelsContained(n.GetLabels(), on.GetLabels()):
+			nodeChanges.Update = append(nodeChanges.Update, name)
+		}
+	}
+	for name, n := range ownedNodes {
+		if _, ok := res.nodes[name]; !ok && prunable(cr, n) {
+			nodeChanges.Delete = append(nodeChanges.Delete, name)
+		}
+	}
+
+	linkChanges := &topov1alpha1.DefinitionPlanChanges{}
+	for name, l := range res.links {
+		ol, ok := ownedLinks[name]
+		switch {
+		case !ok:
+			linkChanges.Create = append(linkChanges.Create, name)
+		case !equality.Semantic.DeepEqual(l.Spec.Properties, ol.Spec.Properties) ||
+			!labelsContained(l.GetLabels(), ol.GetLabels()):
+			linkChanges.Update = append(linkChanges.Update, name)
+		}
+	}
+	for name, l := range ownedLinks {
+		if _, ok := res.links[name]; !ok && prunable(cr, l) {
+			linkChanges.Delete = append(linkChanges.Delete, name)
+		}
+	}
+
+	return &topov1alpha1.DefinitionPlan{
+		Nodes: sortPlanChanges(nodeChanges),
+		Links: sortPlanChanges(linkChanges),
+		Diff:  diffResources(cr, ownedNodes, ownedLinks, res),
+	}, nil
+}
+
+// diffResources returns the differences between the owned and the rendered nodes
+// and links, owned resources which are not pruned are not reported as removed
+func diffResources(cr *topov1alpha1.Definition, ownedNodes map[string]*topov1alpha1.Node, ownedLinks map[string]*topov1alpha1.Link, res *resources) *topov1alpha1.FabricDiff {
+	oldNodes := make([]*fabric.DiffNode, 0, len(ownedNodes))
+	for name, n := range ownedNodes {
+		if _, ok := res.nodes[name]; ok || prunable(cr, n) {
+			oldNodes = append(oldNodes, getDiffNode(n))
+		}
+	}
+	oldLinks := make([]*fabric.DiffLink, 0, len(ownedLinks))
+	for name, l := range ownedLinks {
+		if _, ok := res.links[name]; ok || prunable(cr, l) {
+			oldLinks = append(oldLinks, getDiffLink(l))
+		}
+	}
+	newNodes := make([]*fabric.DiffNode, 0, len(res.nodes))
+	for _, n := range res.nodes {
+		newNodes = append(newNodes, getDiffNode(n))
+	}
+	newLinks := make([]*fabric.DiffLink, 0, len(res.links))
+	for _, l := range res.links {
+		newLinks = append(newLinks, getDiffLink(l))
+	}
+	return fabric.DiffResources(oldNodes, oldLinks, newNodes, newLinks)
+}
+
+func getDiffNode(n *topov1alpha1.Node) *fabric.DiffNode {
+	return &fabric.DiffNode{Name: n.GetName(), Labels: n.GetLabels()}
+}
+
+func getDiffLink(l *topov1alpha1.Link) *fabric.DiffLink {
+	dl := &fabric.DiffLink{Name: l.GetName(), Labels: l.GetLabels(), Lag: l.GetLag()}
+	if l.Spec.Properties != nil && len(l.Spec.Properties.Endpoints) == 2 {
+		dl.NodeA = l.Spec.Properties.Endpoints[0].NodeName
+		dl.InterfaceA = l.Spec.Properties.Endpoints[0].InterfaceName
+		dl.NodeB = l.Spec.Properties.Endpoints[1].NodeName
+		dl.InterfaceB = l.Spec.Properties.Endpoints[1].InterfaceName
+	}
+	return dl
+}
+
+// sortPlanChanges sorts the names so the status does not change between
+// reconciliations of an unchanged definition
+func sortPlanChanges(c *topov1alpha1.DefinitionPlanChanges) *topov1alpha1.DefinitionPlanChanges {
+	sort.Strings(c.Create)
+	sort.Strings(c.Update)
+	sort.Strings(c.Delete)
+	return c
+}
+
+// labelsContained returns true if all labels in desired are set to the same
+// value in actual, labels added by others are ignored
+func labelsContained(desired, actual map[string]string) bool {
+	for k, v := range desired {
+		if actual[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	"github.com/yndd/ndd-runtime/pkg/resource"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+)
+
+const (
+	errPruneNode = "cannot prune node"
+	errPruneLink = "cannot prune link"
+)
+
+// pruneResources deletes the nodes and links owned by the definition that are
+// no longer part of the rendered resources, e.g. when a template shrinks.
+// Nothing is pruned when the definition has an orphan deletion policy and
+// children with an orphan deletion policy are left in place.
+func (r *applogic) pruneResources(ctx context.Context, cr *topov1alpha1.Definition, res *resources) error {
+	log := r.log.WithValues("crName", cr.GetNamespacedName())
+
+	if cr.GetDeletionPolicy() == nddv1.DeletionOrphan {
+		log.Debug("prune skipped", "deletionPolicy", cr.GetDeletionPolicy())
+		return nil
+	}
+
+	// links are deleted first so no link refers to a deleted node
+	ownedLinks, err := r.getOwnedLinks(ctx, cr)
+	if err != nil {
+		return err
+	}
+	for name, l := range ownedLinks {
+		if _, ok := res.links[name]; ok {
+			continue
+		}
+		if !prunable(cr, l) {
+			log.Debug("prune link skipped", "name", name, "deletionPolicy", l.GetDeletionPolicy())
+			continue
+		}
+		log.Debug("prune link", "name", name)
+		if err := r.client.Delete(ctx, l); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errPruneLink)
+		}
+	}
+
+	ownedNodes, err := r.getOwnedNodes(ctx, cr)
+	if err != nil {
+		return err
+	}
+	for name, n := range ownedNodes {
+		if _, ok := res.nodes[name]; ok {
+			continue
+		}
+		if !prunable(cr, n) {
+			log.Debug("prune node skipped", "name", name, "deletionPolicy", n.GetDeletionPolicy())
+			continue
+		}
+		log.Debug("prune node", "name", name)
+		if err := r.client.Delete(ctx, n); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errPruneNode)
+		}
+	}
+	return nil
+}
+
+// prunable returns true if a stale child of the definition can be deleted
+func prunable(cr *topov1alpha1.Definition, child resource.Lifecycle) bool {
+	return cr.GetDeletionPolicy() != nddv1.DeletionOrphan &&
+		child.GetDeletionPolicy() != nddv1.DeletionOrphan
+}
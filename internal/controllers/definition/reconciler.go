@@ -18,7 +18,6 @@ package definition
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -46,6 +45,7 @@ const (
 	//veryShortWait    = 1 * time.Second
 	// errors
 	errUnexpectedResource = "unexpected object"
+	errListTargets        = "cannot list targets of discovery rule"
 	errGetK8sResource     = "cannot get organization resource"
 )
 
@@ -79,6 +79,12 @@ func Setup(mgr ctrl.Manager, nddcopts *shared.NddControllerOptions) error {
 		ctx:    context.Background(),
 	}
 
+	templateHandler := &EnqueueRequestForAllTemplates{
+		client: mgr.GetClient(),
+		log:    nddcopts.Logger,
+		ctx:    context.Background(),
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(nddcopts.Copts).
@@ -86,6 +92,7 @@ func Setup(mgr ctrl.Manager, nddcopts *shared.NddControllerOptions) error {
 		Owns(&topov1alpha1.Definition{}).
 		WithEventFilter(resource.IgnoreUpdateWithoutGenerationChangePredicate()).
 		Watches(&source.Kind{Type: &targetv1.Target{}}, targetHandler).
+		Watches(&source.Kind{Type: &topov1alpha1.Template{}}, templateHandler).
 		Complete(r)
 }
 
@@ -120,10 +127,21 @@ func (r *applogic) Timeout(ctx context.Context, mr resource.Managed) time.Durati
 }
 
 func (r *applogic) Delete(ctx context.Context, mr resource.Managed) (bool, error) {
-	return true, nil
+	cr, ok := mr.(*topov1alpha1.Definition)
+	if !ok {
+		return false, errors.New(errUnexpectedResource)
+	}
+	return r.teardown(ctx, cr)
 }
 
 func (r *applogic) FinalDelete(ctx context.Context, mr resource.Managed) {
+	cr, ok := mr.(*topov1alpha1.Definition)
+	if !ok {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	delete(r.intents, cr.GetNamespacedName())
 }
 
 func (r *applogic) populateSchema(ctx context.Context, mr resource.Managed) error {
@@ -144,30 +162,36 @@ func (r *applogic) populateSchema(ctx context.Context, mr resource.Managed) erro
 	// +++++ GET RESOURCES  +++++
 	// +++++ CREATE INTENT +++++
 
-	// create a topology
-	topo := renderTopology(cr)
-	if err := r.client.Apply(ctx, topo); err != nil {
+	res := newResources()
+
+	// per template render the fabric
+	templates, err := r.renderTemplates(ctx, cr, res)
+	if err != nil {
 		return err
 	}
-
-	// per template create the fabric
-	for _, dt := range cr.Spec.Properties.Templates {
-		log.Debug("NamespacedName input", "dt.NamespacedName", dt.NamespacedName)
-		name, namespace := meta.NamespacedName(dt.NamespacedName).GetNameAndNamespace()
-		log.Debug("NamespacedName output", "namespace", namespace, "name", name)
-		tmpl := &topov1alpha1.Template{}
-		if err := r.client.Get(ctx, types.NamespacedName{
-			Namespace: namespace,
-			Name:      name,
-		}, tmpl); err != nil {
-			// template not defined
-			return err
+	// allocations of templates which are no longer used by the definition are released
+	if !cr.GetPlan() {
+		for tmplName := range cr.Status.Allocations {
+			if _, ok := templates[tmplName]; !ok {
+				delete(cr.Status.Allocations, tmplName)
+			}
 		}
-		if err := r.createFabric(ctx, cr, tmpl); err != nil {
+	}
+
+	// create a topology with the vendor types of the templates, in plan mode nothing is applied
+	topo := renderTopology(cr, res.vendorTypeInfo)
+	if !cr.GetPlan() {
+		if err := r.client.Apply(ctx, topo); err != nil {
 			return err
 		}
 	}
 
+	// discovered targets are bound to the planned fabric nodes with the same identity
+	b, err := r.newNodeBinder(ctx, cr, res)
+	if err != nil {
+		return err
+	}
+
 	// +++++ BREAKDOWN  +++++
 	// per discovery rule check if the discovery rule matches within the namespace
 	for _, dr := range cr.Spec.Properties.DiscoveryRules {
@@ -178,8 +202,12 @@ func (r *applogic) populateSchema(ctx context.Context, mr resource.Managed) erro
 			client.InNamespace(namespace),
 		}
 		// get targets in the namespace based on the discovery rule
+		// the discovered nodes are pruned when they are not rendered, so the
+		// definition cannot be rendered without the targets of every rule
 		tl := &targetv1.TargetList{}
-		r.client.List(ctx, tl, opts...)
+		if err := r.client.List(ctx, tl, opts...); err != nil {
+			return errors.Wrap(err, errListTargets)
+		}
 
 		for _, t := range tl.Items {
 			// create a node
@@ -202,17 +230,22 @@ func (r *applogic) populateSchema(ctx context.Context, mr resource.Managed) erro
 				n.Platform = *t.GetDiscoveryInfo().Kind
 			*/
 
-			n := renderNode(dr.NamespacedName, cr, &t)
-			if err := r.client.Apply(ctx, n); err != nil {
-				return err
+			if name, ok := b.bind(&t); ok {
+				if dr.DigitalTwin {
+					res.addDigitalTwinNode(name)
+				}
+				continue
 			}
+			n := renderNode(dr.NamespacedName, cr, &t)
 
-			switch n.Spec.Properties.VendorType {
-			case targetv1.VendorTypeNokiaSRL:
-				// populate data structure
-			case targetv1.VendorTypeNokiaSROS:
-			default:
-				return fmt.Errorf("unsupported vendor type: %s", n.Spec.Properties.VendorType)
+			// targets of other vendor types are discovered by the rule but not part of the topology
+			if !IsSupportedVendorType(n.Spec.Properties.VendorType) {
+				log.Debug("unsupported vendor type", "target", t.GetName(), "vendorType", n.Spec.Properties.VendorType)
+				continue
+			}
+			res.addNode(n)
+			if dr.DigitalTwin {
+				res.addDigitalTwinNode(n.GetName())
 			}
 
 			// create a state object per vendor type
@@ -220,6 +253,35 @@ func (r *applogic) populateSchema(ctx context.Context, mr resource.Managed) erro
 		}
 	}
 
+	cr.Status.Bindings = b.getBindings()
+
+	if err := r.renderDigitalTwin(ctx, cr, res); err != nil {
+		return err
+	}
+	if err := r.renderCablingPlan(ctx, cr, res); err != nil {
+		return err
+	}
+
+	// in plan mode the changes are reported in the status and not applied
+	if cr.GetPlan() {
+		plan, err := r.planResources(ctx, cr, res)
+		if err != nil {
+			return err
+		}
+		cr.Status.Plan = plan
+		return nil
+	}
+	cr.Status.Plan = nil
+	if err := r.updateNodes(ctx, b); err != nil {
+		return err
+	}
+	if err := r.applyResources(ctx, res); err != nil {
+		return err
+	}
+	if err := r.pruneResources(ctx, cr, res); err != nil {
+		return err
+	}
+
 	// **** COLLECT ALL                                  *****
 	// **** FEEDBACK TO TOP LEVEL                        *****
 	// **** SUBSCRIPTION WITH HANDLER (CREATE LINK/NODE) *****
@@ -228,33 +290,74 @@ func (r *applogic) populateSchema(ctx context.Context, mr resource.Managed) erro
 	return nil
 }
 
-func (r *applogic) createFabric(ctx context.Context, cr *topov1alpha1.Definition, tmpl *topov1alpha1.Template) error {
+// renderTemplates renders the fabric of every template of the definition and returns
+// the namespaced names of the templates
+func (r *applogic) renderTemplates(ctx context.Context, cr *topov1alpha1.Definition, res *resources) (map[string]struct{}, error) {
+	log := r.log.WithValues("crName", cr.GetNamespacedName())
+	templates := map[string]struct{}{}
+	for _, dt := range cr.Spec.Properties.Templates {
+		log.Debug("NamespacedName input", "dt.NamespacedName", dt.NamespacedName)
+		name, namespace := meta.NamespacedName(dt.NamespacedName).GetNameAndNamespace()
+		log.Debug("NamespacedName output", "namespace", namespace, "name", name)
+		tmpl := &topov1alpha1.Template{}
+		if err := r.client.Get(ctx, types.NamespacedName{
+			Namespace: namespace,
+			Name:      name,
+		}, tmpl); err != nil {
+			// template not defined
+			return nil, err
+		}
+		if err := r.renderFabric(ctx, cr, tmpl, dt.DigitalTwin, res); err != nil {
+			return nil, err
+		}
+		templates[tmpl.GetNamespacedName()] = struct{}{}
+	}
+	return templates, nil
+}
+
+// renderFabric renders the nodes and links of the fabric of a template, when digitalTwin
+// is set they are also part of the digital twin of the definition
+func (r *applogic) renderFabric(ctx context.Context, cr *topov1alpha1.Definition, tmpl *topov1alpha1.Template, digitalTwin bool, res *resources) error {
 	crName := cr.GetNamespacedName()
 	log := r.log.WithValues("crName", crName)
-	log.Debug("createFabric...")
+	log.Debug("renderFabric...")
+
+	profiles, err := fabric.GetInterfaceProfiles(ctx, r.client, cr.GetNamespace())
+	if err != nil {
+		return err
+	}
 
 	f, err := fabric.NewFabric(tmpl.GetNamespacedName(), tmpl.Spec.Properties.Fabric,
 		fabric.WithLogger(r.log),
 		fabric.WithClient(r.client),
+		fabric.WithInterfaceProfiles(profiles),
+		fabric.WithAllocations(cr.GetAllocations(tmpl.GetNamespacedName())),
 	)
 	if err != nil {
 		return err
 	}
+	// the allocations are only persisted when the fabric is applied
+	if !cr.GetPlan() {
+		cr.SetAllocations(tmpl.GetNamespacedName(), f.GetAllocations())
+	}
 	f.PrintNodes()
 	f.PrintLinks()
+	f.ExportCablingPlan(res.cablingPlan)
 	for _, fn := range f.GetFabricNodes() {
-		node := renderFabricNode(cr, fn)
-		if err := r.client.Apply(ctx, node); err != nil {
-			return err
+		n := renderFabricNode(cr, fn)
+		res.addNode(n)
+		res.addVendorTypeInfo(fn.GetVendorType(), fn.GetPlatform())
+		if digitalTwin {
+			res.addDigitalTwinNode(n.GetName())
 		}
 	}
 
 	for _, fl := range f.GetFabricLinks() {
-		link := renderFabricLink(cr, fl)
-		if err := r.client.Apply(ctx, link); err != nil {
-			return err
+		l := renderFabricLink(cr, fl)
+		res.addLink(l)
+		if digitalTwin {
+			res.addDigitalTwinLink(l.GetName())
 		}
-
 	}
 
 	return nil
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"sort"
+
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/ndd-runtime/pkg/resource"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RenderResources renders the nodes and links the controller applies for the templates
+// of the definition, sorted by name. The client resolves the templates, the resources
+// they refer to and the interface profiles, so the resources can be rendered without
+// cluster. Discovered targets are not part of the rendered resources.
+func RenderResources(ctx context.Context, c client.Client, log logging.Logger, cr *topov1alpha1.Definition) ([]*topov1alpha1.Node, []*topov1alpha1.Link, error) {
+	r := &applogic{
+		client: resource.ClientApplicator{
+			Client:     c,
+			Applicator: resource.NewAPIPatchingApplicator(c),
+		},
+		log: log,
+	}
+	res := newResources()
+	if cr.Spec.Properties != nil {
+		if _, err := r.renderTemplates(ctx, cr, res); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	nodes := make([]*topov1alpha1.Node, 0, len(res.nodes))
+	for _, n := range res.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetName() < nodes[j].GetName()
+	})
+	links := make([]*topov1alpha1.Link, 0, len(res.links))
+	for _, l := range res.links {
+		links = append(links, l)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].GetName() < links[j].GetName()
+	})
+	return nodes, links, nil
+}
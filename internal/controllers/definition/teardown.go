@@ -0,0 +1,183 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	"github.com/yndd/ndd-runtime/pkg/resource"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errTeardownLink     = "cannot delete link"
+	errTeardownNode     = "cannot delete node"
+	errTeardownTopology = "cannot delete topology"
+	errRelease          = "cannot release child"
+)
+
+// teardown stages of a definition
+const (
+	teardownStageLeafLinks = "leaf links"
+	teardownStageLinks     = "spine/superspine links"
+	teardownStageNodes     = "nodes"
+	teardownStageTopology  = "topology"
+)
+
+// teardown deletes the children of the definition in stages: the links of the
+// leafs, the remaining spine/superspine links, the nodes and finally the topology.
+// A stage only starts when all children of the previous stage are gone. It returns
+// true when all children are deleted, otherwise the progress is reported in the
+// conditions of the definition. Children which are not prunable because of an
+// orphan deletion policy are released instead of deleted.
+func (r *applogic) teardown(ctx context.Context, cr *topov1alpha1.Definition) (bool, error) {
+	log := r.log.WithValues("crName", cr.GetNamespacedName())
+
+	ownedNodes, err := r.getOwnedNodes(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	ownedLinks, err := r.getOwnedLinks(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+
+	leafLinks := []client.Object{}
+	links := []client.Object{}
+	for _, l := range ownedLinks {
+		if !prunable(cr, l) {
+			if err := r.release(ctx, cr, l); err != nil {
+				return false, err
+			}
+			continue
+		}
+		if isLeafLink(cr, l, ownedNodes) {
+			leafLinks = append(leafLinks, l)
+		} else {
+			links = append(links, l)
+		}
+	}
+	if len(leafLinks) > 0 {
+		return false, r.teardownStage(ctx, cr, teardownStageLeafLinks, leafLinks, errTeardownLink)
+	}
+	if len(links) > 0 {
+		return false, r.teardownStage(ctx, cr, teardownStageLinks, links, errTeardownLink)
+	}
+
+	nodes := []client.Object{}
+	for _, n := range ownedNodes {
+		if !prunable(cr, n) {
+			if err := r.release(ctx, cr, n); err != nil {
+				return false, err
+			}
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) > 0 {
+		return false, r.teardownStage(ctx, cr, teardownStageNodes, nodes, errTeardownNode)
+	}
+
+	topo := &topov1alpha1.Topology{}
+	if err := r.client.Get(ctx, types.NamespacedName{
+		Namespace: cr.GetNamespace(),
+		Name:      cr.GetName(),
+	}, topo); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			return false, errors.Wrap(err, errTeardownTopology)
+		}
+		log.Debug("teardown complete")
+		return true, nil
+	}
+	if !metav1.IsControlledBy(topo, cr) {
+		log.Debug("teardown complete", "topology", "not controlled")
+		return true, nil
+	}
+	if !prunable(cr, topo) {
+		if err := r.release(ctx, cr, topo); err != nil {
+			return false, err
+		}
+		log.Debug("teardown complete", "topology", "released")
+		return true, nil
+	}
+	return false, r.teardownStage(ctx, cr, teardownStageTopology, []client.Object{topo}, errTeardownTopology)
+}
+
+// release removes the owner reference of the definition from a child, such that the
+// child is kept when the definition is deleted
+func (r *applogic) release(ctx context.Context, cr *topov1alpha1.Definition, o client.Object) error {
+	r.log.Debug("teardown release", "name", o.GetName(), "deletionPolicy", cr.GetDeletionPolicy())
+	patch := client.MergeFrom(o.DeepCopyObject().(client.Object))
+	refs := make([]metav1.OwnerReference, 0, len(o.GetOwnerReferences()))
+	for _, ref := range o.GetOwnerReferences() {
+		if ref.UID != cr.GetUID() {
+			refs = append(refs, ref)
+		}
+	}
+	o.SetOwnerReferences(refs)
+	if err := r.client.Patch(ctx, o, patch); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errRelease)
+	}
+	return nil
+}
+
+// teardownStage deletes the objects of a stage that are not yet being deleted
+// and reports the remaining objects in the conditions of the definition
+func (r *applogic) teardownStage(ctx context.Context, cr *topov1alpha1.Definition, stage string, objs []client.Object, errMsg string) error {
+	names := make([]string, 0, len(objs))
+	for _, o := range objs {
+		names = append(names, o.GetName())
+		if o.GetDeletionTimestamp() != nil {
+			// deletion is in progress, wait until the object is gone
+			continue
+		}
+		r.log.Debug("teardown", "stage", stage, "name", o.GetName())
+		if err := r.client.Delete(ctx, o); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errMsg)
+		}
+	}
+	sort.Strings(names)
+	cr.SetConditions(nddv1.Deleting().WithMessage(
+		fmt.Sprintf("deleting %s, %d remaining: %s", stage, len(names), strings.Join(names, ", "))))
+	return nil
+}
+
+// isLeafLink returns true if one of the endpoints of the link is a leaf or borderleaf
+func isLeafLink(cr *topov1alpha1.Definition, l *topov1alpha1.Link, nodes map[string]*topov1alpha1.Node) bool {
+	if l.Spec.Properties == nil {
+		return false
+	}
+	for _, ep := range l.Spec.Properties.Endpoints {
+		n, ok := nodes[strings.Join([]string{cr.GetName(), ep.NodeName}, ".")]
+		if !ok || n.Spec.Properties == nil {
+			continue
+		}
+		switch n.Spec.Properties.Position {
+		case topov1alpha1.PositionLeaf, topov1alpha1.PositionBorderLeaf:
+			return true
+		}
+	}
+	return false
+}
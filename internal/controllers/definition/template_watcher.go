@@ -0,0 +1,139 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/fabric"
+)
+
+type EnqueueRequestForAllTemplates struct {
+	client client.Client
+	log    logging.Logger
+	ctx    context.Context
+}
+
+// Create enqueues a request for all definitions which use the template.
+func (e *EnqueueRequestForAllTemplates) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Update enqueues a request for all definitions which use the template.
+func (e *EnqueueRequestForAllTemplates) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.ObjectOld, q)
+	e.add(evt.ObjectNew, q)
+}
+
+// Delete enqueues a request for all definitions which use the template.
+func (e *EnqueueRequestForAllTemplates) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Generic enqueues a request for all definitions which use the template.
+func (e *EnqueueRequestForAllTemplates) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+func (e *EnqueueRequestForAllTemplates) add(obj runtime.Object, queue adder) {
+	cr, ok := obj.(*topov1alpha1.Template)
+	if !ok {
+		return
+	}
+	log := e.log.WithValues("event handler", "Template", "namespace", cr.GetNamespace(), "name", cr.GetName())
+	log.Debug("handleEvent")
+
+	tl := &topov1alpha1.TemplateList{}
+	if err := e.client.List(e.ctx, tl); err != nil {
+		log.Debug("cannot get template list", "error", err)
+		return
+	}
+	tdl := &topov1alpha1.DefinitionList{}
+	if err := e.client.List(e.ctx, tdl); err != nil {
+		log.Debug("cannot get topology definition list", "error", err)
+		return
+	}
+
+	// templates of a definition, used to resolve definition references
+	defTemplates := map[string][]string{}
+	for _, td := range tdl.Items {
+		if td.Spec.Properties == nil {
+			continue
+		}
+		for _, dt := range td.Spec.Properties.Templates {
+			defTemplates[td.GetNamespacedName()] = append(defTemplates[td.GetNamespacedName()],
+				fabric.NamespacedReference(dt.NamespacedName, td.GetNamespace()))
+		}
+	}
+
+	// the templates affected by the change are the template itself and the
+	// templates that refer to an affected template directly or through a definition
+	affected := map[string]struct{}{cr.GetNamespacedName(): {}}
+	for changed := true; changed; {
+		changed = false
+		for _, t := range tl.Items {
+			if _, ok := affected[t.GetNamespacedName()]; ok || t.Spec.Properties.Fabric == nil {
+				continue
+			}
+			if refersToTemplate(&t, affected, defTemplates) {
+				affected[t.GetNamespacedName()] = struct{}{}
+				changed = true
+			}
+		}
+	}
+
+	for _, td := range tdl.Items {
+		for _, tmplName := range defTemplates[td.GetNamespacedName()] {
+			if _, ok := affected[tmplName]; ok {
+				log.Debug("enqueue definition", "definition", td.GetNamespacedName(), "template", tmplName)
+				queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: td.GetNamespace(),
+					Name:      td.GetName()}})
+				break
+			}
+		}
+	}
+}
+
+// refersToTemplate returns true if a pod of the template refers to one of the
+// templates, either with a template reference or a definition reference
+func refersToTemplate(t *topov1alpha1.Template, templates map[string]struct{}, defTemplates map[string][]string) bool {
+	for _, pod := range t.Spec.Properties.Fabric.Pod {
+		if pod.TemplateReference != nil {
+			if _, ok := templates[fabric.NamespacedReference(*pod.TemplateReference, t.GetNamespace())]; ok {
+				return true
+			}
+		}
+		if pod.DefinitionReference != nil {
+			for _, tmplName := range defTemplates[fabric.NamespacedReference(*pod.DefinitionReference, t.GetNamespace())] {
+				if _, ok := templates[tmplName]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
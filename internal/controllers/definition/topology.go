@@ -23,7 +23,22 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func renderTopology(cr *topov1alpha1.Definition) *topov1alpha1.Topology { // nolint:interfacer,gocyclo
+// renderTopology renders the topology of the definition, the vendor type info of the
+// definition takes precedence over the vendor type info derived from the templates.
+// The defaults of the topology are the defaults of the definition.
+func renderTopology(cr *topov1alpha1.Definition, vendorTypeInfo []*topov1alpha1.NodeProperties) *topov1alpha1.Topology { // nolint:interfacer,gocyclo
+	vti := make([]*topov1alpha1.NodeProperties, 0, len(vendorTypeInfo))
+	overrides := map[targetv1.VendorType]struct{}{}
+	for _, vt := range cr.GetVendorTypeInfo() {
+		vti = append(vti, vt)
+		overrides[vt.VendorType] = struct{}{}
+	}
+	for _, vt := range vendorTypeInfo {
+		if _, ok := overrides[vt.VendorType]; !ok {
+			vti = append(vti, vt)
+		}
+	}
+
 	return &topov1alpha1.Topology{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            cr.GetName(),
@@ -33,24 +48,26 @@ func renderTopology(cr *topov1alpha1.Definition) *topov1alpha1.Topology { // nol
 		},
 		Spec: topov1alpha1.TopologySpec{
 			Properties: topov1alpha1.TopologyProperties{
-				Defaults: &topov1alpha1.TopologyDefaults{
-					NodeProperties: &topov1alpha1.NodeProperties{
-						Position: topov1alpha1.PositionInfra,
-					},
-				},
-				VendorTypeInfo: []*topov1alpha1.NodeProperties{
-					{
-						VendorType: targetv1.VendorTypeNokiaSRL,
-						Platform:   "7220 IXR-D2",
-						Position:   topov1alpha1.PositionInfra,
-					},
-					{
-						VendorType: targetv1.VendorTypeNokiaSROS,
-						Platform:   "7750 SR1",
-						Position:   topov1alpha1.PositionInfra,
-					},
-				},
+				Defaults:       cr.GetDefaults().DeepCopy(),
+				VendorTypeInfo: vti,
 			},
 		},
 	}
 }
+
+// addVendorTypeInfo adds the platform of a vendor type, the first platform used
+// for a vendor type is its default platform in the topology
+func (x *resources) addVendorTypeInfo(vendorType targetv1.VendorType, platform string) {
+	if vendorType == "" {
+		return
+	}
+	for _, vt := range x.vendorTypeInfo {
+		if vt.VendorType == vendorType {
+			return
+		}
+	}
+	x.vendorTypeInfo = append(x.vendorTypeInfo, &topov1alpha1.NodeProperties{
+		VendorType: vendorType,
+		Platform:   platform,
+	})
+}
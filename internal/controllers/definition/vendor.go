@@ -0,0 +1,56 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"sync"
+
+	targetv1 "github.com/yndd/target/apis/target/v1"
+)
+
+// supportedVendorTypes is the allow-list of vendor types of the discovered targets
+// which are rendered as nodes of the topology
+var supportedVendorTypes = &vendorTypes{
+	vendorTypes: map[targetv1.VendorType]struct{}{
+		targetv1.VendorTypeNokiaSRL:  {},
+		targetv1.VendorTypeNokiaSROS: {},
+	},
+}
+
+type vendorTypes struct {
+	m           sync.RWMutex
+	vendorTypes map[targetv1.VendorType]struct{}
+}
+
+// RegisterVendorTypes adds vendor types to the allow-list of vendor types of the
+// discovered targets, it should be called before the controllers are started.
+func RegisterVendorTypes(vts ...targetv1.VendorType) {
+	supportedVendorTypes.m.Lock()
+	defer supportedVendorTypes.m.Unlock()
+	for _, vt := range vts {
+		supportedVendorTypes.vendorTypes[vt] = struct{}{}
+	}
+}
+
+// IsSupportedVendorType returns true if discovered targets of the vendor type are
+// rendered as nodes
+func IsSupportedVendorType(vt targetv1.VendorType) bool {
+	supportedVendorTypes.m.RLock()
+	defer supportedVendorTypes.m.RUnlock()
+	_, ok := supportedVendorTypes.vendorTypes[vt]
+	return ok
+}
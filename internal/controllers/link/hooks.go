@@ -39,19 +39,14 @@ const (
 type Hooks interface {
 	// Get performs operations to validate the child resources
 	Get(context.Context, *topov1alpha1.Link, string) (*topov1alpha1.Link, error)
-
 	// Create performs operations to deploy the child resources
 	Create(context.Context, *topov1alpha1.Link, string) error
-
 	// Delete performs operations to deploy the child resources
 	Delete(context.Context, *topov1alpha1.Link) error
-
 	// apply performs operations to update the resource
 	Apply(context.Context, *topov1alpha1.Link, *topov1alpha1.Link) error
-
 	// apply performs operations to update the resource
 	DeleteApply(context.Context, *topov1alpha1.Link, *topov1alpha1.Link) error
-
 	// deletes the node/interfacename from the endpoint tags
 	DeleteApplyNode(context.Context, *topov1alpha1.Link, int, string, string) error
 }
@@ -87,36 +82,40 @@ func (h *Hook) Create(ctx context.Context, cr *topov1alpha1.Link, topologyName s
 }
 
 func (h *Hook) Delete(ctx context.Context, cr *topov1alpha1.Link) error {
-	if err := h.client.Delete(ctx, cr); err != nil {
+	if err := h.client.Delete(ctx, cr); resource.IgnoreNotFound(err) != nil {
 		return errors.Wrap(err, errDeleteLink)
 	}
-
 	return nil
 }
 
+// the logical link is updated and not patched such that deleted tags are removed
+
 func (h *Hook) Apply(ctx context.Context, cr *topov1alpha1.Link, mhtl *topov1alpha1.Link) error {
 	tl := updateLogicalTopologyLink(cr, mhtl)
-	if err := h.client.Apply(ctx, tl); err != nil {
-		return errors.Wrap(err, errDeleteLink)
+	if err := h.client.Update(ctx, tl); err != nil {
+		return errors.Wrap(err, errApplyLink)
 	}
-
 	return nil
 }
 
+// DeleteApply removes the member link from the logical link, when no member
+// links remain the logical link is deleted
 func (h *Hook) DeleteApply(ctx context.Context, cr *topov1alpha1.Link, mhtl *topov1alpha1.Link) error {
 	tl := updateDeleteLogicalTopologyLink(cr, mhtl)
-	if err := h.client.Apply(ctx, tl); err != nil {
-		return errors.Wrap(err, errDeleteLink)
+	if len(tl.GetOwnerReferences()) == 0 {
+		h.log.Debug("last member link deleted, delete the logical link", "logical link name", tl.GetName())
+		return h.Delete(ctx, tl)
+	}
+	if err := h.client.Update(ctx, tl); err != nil {
+		return errors.Wrap(err, errApplyLink)
 	}
-
 	return nil
 }
 
-func (h *Hook) DeleteApplyNode(ctx context.Context, cr *topov1alpha1.Link, i int, nodeName, interfaceName string) error {
-	tl := updateDeleteLogicalTopologyLinkNodeEndpoint(cr, i, nodeName, interfaceName)
-	if err := h.client.Apply(ctx, tl); err != nil {
-		return errors.Wrap(err, errDeleteLink)
+func (h *Hook) DeleteApplyNode(ctx context.Context, cr *topov1alpha1.Link, i int, nodeTag, lagName string) error {
+	tl := updateDeleteLogicalTopologyLinkNodeEndpoint(cr, i, nodeTag, lagName)
+	if err := h.client.Update(ctx, tl); err != nil {
+		return errors.Wrap(err, errApplyLink)
 	}
-
 	return nil
 }
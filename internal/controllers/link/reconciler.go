@@ -30,12 +30,13 @@ import (
 	"github.com/yndd/ndd-runtime/pkg/resource"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
 	"github.com/yndd/ndd-runtime/pkg/shared"
 	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
-	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -45,6 +46,10 @@ const (
 	// errors
 	errUnexpectedResource = "unexpected organization object"
 	errGetK8sResource     = "cannot get organization resource"
+	errIndexLinks         = "cannot index links by node"
+	// indexes
+	// linkNodeIndex indexes the links by the full name of their endpoint nodes
+	linkNodeIndex = "spec.properties.endpoints.nodeName"
 )
 
 // Setup adds a controller that reconciles infra.
@@ -54,6 +59,10 @@ func Setup(mgr ctrl.Manager, nddcopts *shared.NddControllerOptions) error {
 	//tllfn := func() topov1alpha1.TlList { return &topov1alpha1.TopologyLinkList{} }
 	//tpfn := func() topov1alpha1.Tp { return &topov1alpha1.Topology{} }
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &topov1alpha1.Link{}, linkNodeIndex, indexLinkNodes); err != nil {
+		return errors.Wrap(err, errIndexLinks)
+	}
+
 	c := resource.ClientApplicator{
 		Client:     mgr.GetClient(),
 		Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
@@ -109,6 +118,27 @@ func Setup(mgr ctrl.Manager, nddcopts *shared.NddControllerOptions) error {
 		Complete(r)
 }
 
+// indexLinkNodes returns the full names of the endpoint nodes of a link
+func indexLinkNodes(o client.Object) []string {
+	cr, ok := o.(*topov1alpha1.Link)
+	if !ok {
+		return nil
+	}
+	nodeNames := []string{}
+	for _, ep := range cr.GetEndpoints() {
+		if ep.NodeName == "" {
+			continue
+		}
+		nodeNames = append(nodeNames, getFullNodeName(cr, ep.NodeName))
+	}
+	return nodeNames
+}
+
+// getFullNodeName returns the name of the node resource of the endpoint node of the link
+func getFullNodeName(cr *topov1alpha1.Link, nodeName string) string {
+	return strings.Join([]string{odns.GetParentResourceName(cr.GetName()), nodeName}, ".")
+}
+
 type application struct {
 	client resource.ClientApplicator
 	log    logging.Logger
@@ -175,25 +205,31 @@ func (r *application) Timeout(ctx context.Context, mr resource.Managed) time.Dur
 }
 
 func (r *application) Delete(ctx context.Context, mr resource.Managed) (bool, error) {
-	/*
-		cr, ok := mg.(*topov1alpha1.TopologyLink)
-		if !ok {
-			return true, errors.New(errUnexpectedResource)
-		}
-		if cr.GetLagMember() {
-			topologyName := cr.GetTopologyName()
-			logicalLink, err := r.hooks.Get(ctx, cr, topologyName)
-			if err == nil {
-				r.log.Debug("logical link exists", "Logical Link", logicalLink.GetName())
-				//for the multi-homed case we need to delete the tags of the member links
-				// that match the mh name
-				if err := r.hooks.DeleteApply(ctx, cr, logicalLink); err != nil {
-					r.log.Debug("Cannot delete tags of a logical link", "error", err)
-					return true, err
-				}
+	cr, ok := mr.(*topov1alpha1.Link)
+	if !ok {
+		return false, errors.New(errUnexpectedResource)
+	}
+	// the logical link of members controlled by another controller is maintained by that controller
+	if cr.GetLagMember() && metav1.GetControllerOf(cr) == nil {
+		logicalLink, err := r.hooks.Get(ctx, cr, cr.GetTopologyName())
+		if err != nil {
+			if resource.IgnoreNotFound(err) != nil {
+				return false, err
 			}
+			return true, nil
 		}
-	*/
+		// logical links rendered by another controller are not maintained by the member links
+		if metav1.GetControllerOf(logicalLink) != nil {
+			return true, nil
+		}
+		r.log.Debug("logical link exists", "Logical Link", logicalLink.GetName())
+		// remove the member from the logical link, for the multi-homed case the tags
+		// of the member node are deleted as well
+		if err := r.hooks.DeleteApply(ctx, cr, logicalLink); err != nil {
+			r.log.Debug("Cannot delete member of a logical link", "error", err)
+			return false, err
+		}
+	}
 	return true, nil
 }
 
@@ -229,10 +265,12 @@ func (r *application) handleAppLogic(ctx context.Context, cr *topov1alpha1.Link)
 		//cr.SetReason("topology not found")
 		return nil, errors.Wrap(err, "topology not found")
 	}
-	if topo.GetCondition(nddv1.ConditionKindReady).Status != corev1.ConditionTrue {
+	// the topology readiness reflects its nodes and links, so they only depend on
+	// the topology being reconciled
+	if !topo.IsReconciled() {
 		//cr.SetStatus("down")
 		//cr.SetReason("topology not found or ready")
-		return nil, errors.New("topology not ready")
+		return nil, errors.New("topology not synced")
 	}
 
 	// topology found and ready
@@ -241,10 +279,17 @@ func (r *application) handleAppLogic(ctx context.Context, cr *topov1alpha1.Link)
 		return nil, err
 	}
 
-	_, err := r.parseLink(ctx, cr, fullTopoName)
+	c, err := r.parseLink(ctx, cr, fullTopoName)
 	if err != nil {
 		return nil, err
 	}
+	if c != nil {
+		// the managed reconciler marks the link available when the update succeeds,
+		// an invalid link is reported as an error such that it is not ready
+		cr.SetConditions(*c)
+		return nil, errors.New(c.Message)
+	}
+	cr.SetConditions(topov1alpha1.Healthy())
 
 	return make(map[string]string), nil
 }
@@ -273,160 +318,183 @@ func (r *application) handleStatus(ctx context.Context, cr *topov1alpha1.Link, t
 	return nil
 }
 
-func (r *application) parseLink(ctx context.Context, cr *topov1alpha1.Link, fullTopoName string) (*string, error) {
+// parseLink validates the link and maintains the logical link of lag members. When
+// the link is not valid an unhealthy condition is returned.
+func (r *application) parseLink(ctx context.Context, cr *topov1alpha1.Link, fullTopoName string) (*nddv1.Condition, error) {
 	// parse link
 
 	// validates if the nodes if the links are present in the k8s api are not
 	// if an error occurs during validation an error is returned
 	msg, err := r.validateNodes(ctx, cr)
 	if err != nil {
-		return msg, err
+		return nil, err
 	}
 	if msg != nil {
-		return nil, fmt.Errorf("%s", *msg)
+		c := topov1alpha1.Unhealthy(topov1alpha1.ConditionReasonNodeNotFound).WithMessage(*msg)
+		return &c, nil
 	}
 
-	/*
-		// for infra links we set the kind at the link level using the information from the spec
-		if cr.GetEndPointAKind() == topov1alpha1.EndpointKindInfra && cr.GetEndPointBKind() == topov1alpha1.EndpointKindInfra {
-			//TODO
-			//cr.SetKind(topov1alpha1.LinkEPKindInfra.String())
+	// validates no other link in the topology uses the interfaces of the link
+	msg, err = r.validateInterfaces(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if msg != nil {
+		c := topov1alpha1.Unhealthy(topov1alpha1.ConditionReasonInterfaceConflict).WithMessage(*msg)
+		return &c, nil
+	}
+
+	if cr.GetLag() {
+		// this is a logical link (single homes or multihomed), we dont need to process it since the member links take care
+		// of crud operation
+		return nil, nil
+	}
+
+	// check if the link is part of a lag, the logical link of members controlled by
+	// another controller, e.g. a definition, is rendered by that controller
+	if cr.GetLagMember() && metav1.GetControllerOf(cr) == nil {
+		logicalLink, err := r.hooks.Get(ctx, cr, fullTopoName)
+		if err != nil {
+			if resource.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			if err := r.hooks.Create(ctx, cr, fullTopoName); err != nil {
+				return nil, err
+			}
+			r.log.Debug("logical link created")
+			return nil, nil
 		}
+		r.log.Debug("logical link exists", "Logical Link", logicalLink.GetName())
 
-		if cr.GetLag() {
-			// this is a logical link (single homes or multihomed), we dont need to process it since the member links take care
-			// of crud operation
-			cr.SetOrganization(cr.GetOrganization())
-			cr.SetDeployment(cr.GetDeployment())
-			cr.SetAvailabilityZone(cr.GetAvailabilityZone())
-			//cr.SetTopologyName(cr.GetTopologyName())
+		// logical links rendered by another controller are not maintained by the member links
+		if metav1.GetControllerOf(logicalLink) != nil {
 			return nil, nil
 		}
 
-		// check if the link is part of a lag
-		if cr.GetLagMember() {
-			logicalLink, err := r.hooks.Get(ctx, cr, fullTopoName)
-			if err != nil {
-				if resource.IgnoreNotFound(err) != nil {
-					return nil, err
+		// add the member to the logical link, for the multi-homed case the tags
+		// of the member node are added as well
+		if err := r.hooks.Apply(ctx, cr, logicalLink); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// validateNodes validates the nodes of the link exist. Logical links derived from member
+// links are maintained here: a single-homed logical link is deleted when a node no longer
+// exists, for multi-homed logical links the tags of the node are deleted and when none of
+// the member nodes exist the logical link is deleted.
+func (r *application) validateNodes(ctx context.Context, cr *topov1alpha1.Link) (*string, error) {
+	// lag are logical links which are created based on member links
+	derived := cr.GetLag() && metav1.GetControllerOf(cr) == nil
+
+	for i, ep := range cr.GetEndpoints() {
+		if derived && ep.MultiHoming {
+			// node validation happens through the endpoint tags
+			// a nodetag has a prefix of node:
+			found := false
+			for k, v := range ep.Tag {
+				nodeName, ok := getTagNode(k)
+				if !ok {
+					continue
 				}
-				if err := r.hooks.Create(ctx, cr, fullTopoName); err != nil {
+				exists, err := r.nodeExists(ctx, cr, nodeName)
+				if err != nil {
 					return nil, err
 				}
-				r.log.Debug("logical link created")
-				cr.SetOrganization(cr.GetOrganization())
-				cr.SetDeployment(cr.GetDeployment())
-				cr.SetAvailabilityZone(cr.GetAvailabilityZone())
-				//cr.SetTopologyName(cr.GetTopologyName())
-				return nil, nil
-
+				if !exists {
+					r.log.Debug("mh-ep logical-link:: member node not found, delete the ep node tags", "nodeName", nodeName)
+					// node no longer exists, we can delete the node tags from the logical element
+					if err := r.hooks.DeleteApplyNode(ctx, cr, i, k, v); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				found = true
 			}
-			r.log.Debug("logical link exists", "Logical Link", logicalLink.GetName())
-
-			// for the multi-homed case we need to add the tags of the other member links
-			// that match the mh name
-			if err := r.hooks.Apply(ctx, cr, logicalLink); err != nil {
-				return nil, err
+			if !found {
+				// when none of the mh nodes are found we can delete the logical link
+				r.log.Debug("mh-ep logical-link: none of the member nodes were found, delete the logical-link")
+				return nil, r.hooks.Delete(ctx, cr)
 			}
+			continue
+		}
 
+		exists, err := r.nodeExists(ctx, cr, ep.NodeName)
+		if err != nil {
+			return nil, err
 		}
-		cr.SetOrganization(cr.GetOrganization())
-		cr.SetDeployment(cr.GetDeployment())
-		cr.SetAvailabilityZone(cr.GetAvailabilityZone())
-		//cr.SetTopologyName(cr.GetTopologyName())
-	*/
+		if !exists {
+			if derived {
+				r.log.Debug("sh-ep logical-link: node not found, delete the logical-link", "nodeName", ep.NodeName)
+				// node no longer exists, we can delete the logical element
+				return nil, r.hooks.Delete(ctx, cr)
+			}
+			r.log.Debug("individual link: node not found", "nodeName", ep.NodeName)
+			msg := fmt.Sprintf("node %s of endpoint %d not found in topology %s", ep.NodeName, i+1, odns.GetParentResourceName(cr.GetName()))
+			return &msg, nil
+		}
+	}
 	return nil, nil
 }
 
-func (r *application) validateNodes(ctx context.Context, cr *topov1alpha1.Link) (*string, error) {
-	/*
-		for i := 0; i <= 1; i++ {
-			var multihoming bool
-			var nodeName string
-			var tags map[string]string
-			lag := cr.GetLag()
-			switch i {
-			case 0:
-				nodeName = cr.GetEndpointANodeName()
-				multihoming = cr.GetEndPointAMultiHoming()
-				tags = cr.GetEndpointATag()
-			case 1:
-				nodeName = cr.GetEndpointBNodeName()
-				multihoming = cr.GetEndPointBMultiHoming()
-				tags = cr.GetEndpointBTag()
+// validateInterfaces validates the interfaces of the link are not claimed by another
+// link in the same topology. When 2 links claim the same interface the link which
+// was created last is reported.
+func (r *application) validateInterfaces(ctx context.Context, cr *topov1alpha1.Link) (*string, error) {
+	for _, ep := range cr.GetEndpoints() {
+		if ep.NodeName == "" || ep.InterfaceName == "" {
+			continue
+		}
+		// only the links of the topology with an endpoint on the same node are relevant
+		opts := []client.ListOption{
+			client.InNamespace(cr.GetNamespace()),
+			client.MatchingFields{linkNodeIndex: getFullNodeName(cr, ep.NodeName)},
+		}
+		if topoName, ok := cr.GetLabels()[topov1alpha1.LabelKeyTopology]; ok {
+			opts = append(opts, client.MatchingLabels{topov1alpha1.LabelKeyTopology: topoName})
+		}
+		ll := &topov1alpha1.LinkList{}
+		if err := r.client.List(ctx, ll, opts...); err != nil {
+			return nil, err
+		}
+		for _, l := range ll.Items {
+			if l.GetName() == cr.GetName() || !createdBefore(&l, cr) {
+				continue
 			}
-
-			// lag are logical links which are created based on member links
-			// for singlehomed logical links if the node no longer exists, we delete the sh-logical-link
-			// for multi-homed logical links if a member node no longer exists, we delete the tags related to the node
-			// for multi-homed logical links of all member nodes no longer exist, we delete the mh-logical link
-			if lag {
-				if multihoming {
-					// node validation happens through the endpoint tags
-					// a nodetag has a prefix of node:
-					found := false
-					for k, v := range tags {
-						if strings.Contains(k, topov1alpha1.NodePrefix) {
-							nodeName := strings.TrimPrefix(k, topov1alpha1.NodePrefix+":")
-
-							fullNodeName := strings.Join([]string{odns.GetParentResourceName(cr.GetName()), nodeName}, ".")
-							node := &topov1alpha1.Node{}
-							if err := r.client.Get(ctx, types.NamespacedName{
-								Namespace: cr.GetNamespace(),
-								Name:      fullNodeName}, node); err != nil {
-								if resource.IgnoreNotFound(err) != nil {
-									return nil, err
-								}
-								r.log.Debug("mh-ep logical-link:: member node not found, delete the ep node tags", "nodeName", nodeName)
-								// node no longer exists, we can delete the node tags from the logocal element
-								if err := r.hooks.DeleteApplyNode(ctx, cr, 0, k, v); err != nil {
-									return nil, err
-								}
-							} else {
-								found = true
-							}
-						}
-					}
-					if !found {
-						// when none of the mh nodes are found we can delete the logical link
-						if err := r.hooks.Delete(ctx, cr); err != nil {
-							return nil, err
-						}
-						r.log.Debug("mh-ep logical-link: none of the member nodes wwere found, delete the logical-link")
-						return nil, nil
-					}
-				} else {
-					fullNodeName := strings.Join([]string{odns.GetParentResourceName(cr.GetName()), nodeName}, ".")
-					node := &topov1alpha1.Node{}
-					if err := r.client.Get(ctx, types.NamespacedName{
-						Namespace: cr.GetNamespace(),
-						Name:      fullNodeName}, node); err != nil {
-						if resource.IgnoreNotFound(err) != nil {
-							return nil, err
-						}
-						r.log.Debug("sh-ep logical-link: node not found, delete the logical-link", "nodeName", nodeName)
-						// node no longer exists, we can delete the logical element
-						if err := r.hooks.Delete(ctx, cr); err != nil {
-							return nil, err
-						}
-						// when delete is successfull we finish/return
-						return nil, nil
-					}
-				}
-			} else {
-				// individual links
-				fullNodeName := strings.Join([]string{odns.GetParentResourceName(cr.GetName()), nodeName}, ".")
-				node := &topov1alpha1.Node{}
-				if err := r.client.Get(ctx, types.NamespacedName{
-					Namespace: cr.GetNamespace(),
-					Name:      fullNodeName}, node); err != nil {
-					r.log.Debug("individual link: node not found", "nodeName", nodeName)
-					//cr.SetStatus("down")
-					//cr.SetReason(fmt.Sprintf("node %d not found", i))
-					return utils.StringPtr(fmt.Sprintf("node %d not found", i)), nil
+			for _, otherEp := range l.GetEndpoints() {
+				if ep.NodeName == otherEp.NodeName && ep.InterfaceName == otherEp.InterfaceName {
+					r.log.Debug("interface conflict", "nodeName", ep.NodeName, "interfaceName", ep.InterfaceName, "link", l.GetName())
+					msg := fmt.Sprintf("interface %s/%s is already used by link %s", ep.NodeName, ep.InterfaceName, l.GetName())
+					return &msg, nil
 				}
 			}
 		}
-	*/
+	}
 	return nil, nil
 }
+
+// createdBefore returns true if link a was created before link b, links
+// created at the same time are ordered by name
+func createdBefore(a, b *topov1alpha1.Link) bool {
+	ta, tb := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if ta.Equal(&tb) {
+		return a.GetName() < b.GetName()
+	}
+	return ta.Before(&tb)
+}
+
+// nodeExists returns true if the node of the link exists in the topology of the link
+func (r *application) nodeExists(ctx context.Context, cr *topov1alpha1.Link, nodeName string) (bool, error) {
+	fullNodeName := getFullNodeName(cr, nodeName)
+	node := &topov1alpha1.Node{}
+	if err := r.client.Get(ctx, types.NamespacedName{
+		Namespace: cr.GetNamespace(),
+		Name:      fullNodeName}, node); err != nil {
+		if resource.IgnoreNotFound(err) != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
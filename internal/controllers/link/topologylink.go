@@ -17,9 +17,6 @@ limitations under the License.
 package link
 
 import (
-
-	//nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
-
 	"strings"
 
 	"github.com/yndd/app-runtime/pkg/odns"
@@ -29,8 +26,8 @@ import (
 )
 
 const (
-	shPrefix    = "logical-sh-link"
-	mhPrefix    = "logical-mh-link"
+	shPrefix    = topov1alpha1.LogicalSingleHomedLinkPrefix
+	mhPrefix    = topov1alpha1.LogicalMultiHomedLinkPrefix
 	labelPrefix = "nddo-infra"
 )
 
@@ -40,188 +37,126 @@ func buildLogicalTopologyLink(cr *topov1alpha1.Link) *topov1alpha1.Link {
 	// mh-lag-A - sh-lag-B: <org>.<depl>.<topo>.<logical-mh-link>.<multihoming-A-name>.<node-name-epB><lag-name-epB>
 	// sh-lag-A - mh-lag-B: <org>.<depl>.<topo>.<logical-mh-link>.<node-name-epB><lag-name-epB>.<multihoming-A-name>
 	// mh-lag-A - mh-lag-B: <org>.<depl>.<topo>.<logical-mh-link>.<multihoming-A-name>.<multihoming-B-name>
-
-	var (
-		name           string
-		nodeNameA      string
-		nodeNameB      string
-		interfaceNameA string
-		interfaceNameB string
-		epATags        map[string]string
-		epBTags        map[string]string
-	)
-	/*
-		//fmt.Printf("buildLogicalTopologyLink: %s mhA: %t, mhB: %t\n", cr.GetName(), cr.GetEndPointAMultiHoming(), cr.GetEndPointBMultiHoming())
-		if cr.GetEndPointAMultiHoming() || cr.GetEndPointBMultiHoming() {
-			if cr.GetEndPointAMultiHoming() {
-				// multihomed endpoint A
-				name = strings.Join([]string{mhPrefix, cr.GetEndPointAMultiHomingName()}, "-")
-				nodeNameA = ""
-				interfaceNameA = cr.GetEndPointAMultiHomingName()
-				//tagNodeName := strings.Join([]string{topov1alpha1.NodePrefix, cr.GetEndpointANodeName()}, ":")
-
-					epATags = cr.GetEndpointATagRaw()
-					epATags = append(epATags, []*nddov1.Tag{
-						{Key: utils.StringPtr(topov1alpha1.KeyLinkEPMultiHoming), Value: utils.StringPtr("true")},
-						{Key: utils.StringPtr(tagNodeName), Value: utils.StringPtr(cr.GetLagAName())},
-					}...)
-
-			} else {
-				name = strings.Join([]string{name, cr.GetEndpointANodeName()}, "-")
-				nodeNameA = cr.GetEndpointANodeName()
-				interfaceNameA = cr.GetLagAName()
-				//epATags = cr.GetEndpointATagRaw()
-			}
-			if cr.GetEndPointBMultiHoming() {
-				// multihomed endpoint B
-				name = strings.Join([]string{mhPrefix, cr.GetEndPointBMultiHomingName()}, "-")
-				nodeNameB = ""
-				interfaceNameB = cr.GetEndPointAMultiHomingName()
-				//tagNodeName := strings.Join([]string{topov1alpha1.NodePrefix, cr.GetEndpointBNodeName()}, ":")
-					epBTags = cr.GetEndpointBTagRaw()
-					epBTags = append(epBTags, []*nddov1.Tag{
-						{Key: utils.StringPtr(topov1alpha1.KeyLinkEPMultiHoming), Value: utils.StringPtr("true")},
-						{Key: utils.StringPtr(tagNodeName), Value: utils.StringPtr(cr.GetLagBName())},
-					}...)
-			} else {
-				name = strings.Join([]string{name, cr.GetEndpointBNodeName()}, "-")
-				nodeNameB = cr.GetEndpointBNodeName()
-				interfaceNameB = cr.GetLagBName()
-				//epBTags = cr.GetEndpointBTagRaw()
-			}
-			// prepend the topologyname
-
-		} else {
-			name = strings.Join([]string{shPrefix, cr.GetEndpointANodeName(), cr.GetLagAName(), cr.GetEndpointBNodeName(), cr.GetLagBName()}, "-")
-			nodeNameA = cr.GetEndpointANodeName()
-			interfaceNameA = cr.GetLagAName()
-			//epATags = cr.GetEndpointATagRaw()
-			nodeNameB = cr.GetEndpointBNodeName()
-			interfaceNameB = cr.GetLagBName()
-			//epBTags = cr.GetEndpointBTagRaw()
+	var name string
+	if cr.GetEndPointAMultiHoming() || cr.GetEndPointBMultiHoming() {
+		nameA := strings.Join([]string{cr.GetEndpointANodeName(), cr.GetLagAName()}, "-")
+		if cr.GetEndPointAMultiHoming() {
+			nameA = cr.GetEndPointAMultiHomingName()
 		}
-	*/
-
+		nameB := strings.Join([]string{cr.GetEndpointBNodeName(), cr.GetLagBName()}, "-")
+		if cr.GetEndPointBMultiHoming() {
+			nameB = cr.GetEndPointBMultiHomingName()
+		}
+		name = strings.Join([]string{mhPrefix, nameA, nameB}, "-")
+	} else {
+		name = topov1alpha1.LogicalSingleHomedLinkName(cr.GetEndpointANodeName(), cr.GetLagAName(), cr.GetEndpointBNodeName(), cr.GetLagBName())
+	}
 	// prepend the parent logic link
 	name = strings.Join([]string{odns.GetParentResourceName(cr.GetName()), name}, ".")
 
-	//ndda := nddov1.NewOdaInfo()
-	//ndda.SetOrganization(cr.GetOrganization())
-	//ndda.SetDeployment(cr.GetDeployment())
-	//ndda.SetAvailabilityZone(cr.GetAvailabilityZone())
+	endpoints := make([]*topov1alpha1.Endpoints, 0, 2)
+	for _, ep := range cr.GetEndpoints() {
+		endpoints = append(endpoints, buildLogicalEndpoint(ep))
+	}
 
-	//fmt.Printf("buildLogicalTopologyLink: name: %s nodeA: %s, nodeB: %s, itfcA: %s, itfceB: %s\n", name, nodeNameA, nodeNameB, interfaceNameA, interfaceNameB)
-	//fmt.Printf("buildLogicalTopologyLink: epAtags: %v, epBtags: %v\n", cr.GetEndpointATag(), cr.GetEndpointBTag())
+	// the logical link is owned by all its member links, it is deleted when
+	// the last member link is deleted
 	return &topov1alpha1.Link{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       cr.GetNamespace(),
-			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, topov1alpha1.LinkGroupVersionKind))},
+			Labels:          cr.GetLabels(),
+			OwnerReferences: []metav1.OwnerReference{meta.AsOwner(meta.TypedReferenceTo(cr, topov1alpha1.LinkGroupVersionKind))},
 		},
 		Spec: topov1alpha1.LinkSpec{
 			Properties: &topov1alpha1.LinkProperties{
-				Endpoints: []*topov1alpha1.Endpoints{
-					{
-						NodeName:      nodeNameA,
-						InterfaceName: interfaceNameA,
-						Tag:           epATags,
-					},
-					{
-						NodeName:      nodeNameB,
-						InterfaceName: interfaceNameB,
-						Tag:           epBTags,
-					},
-				},
-				Tag: map[string]string{"lag": "true"},
-				/*
-					Tag: []*nddv1.Tag{
-						{Key: utils.StringPtr("lag"), Value: utils.StringPtr("true")},
-					},
-				*/
+				Kind:      cr.Spec.Properties.Kind,
+				Endpoints: endpoints,
+				Lag:       true,
+				// lags derived from member links run lacp
+				Lacp: true,
 			},
-			//TopologyName: utils.StringPtr(topologyName),
-
 		},
-		/*
-			Status: topov1alpha1.TopologyLinkStatus{
-				TopologyLink: &topov1alpha1.NddrTopologyTopologyLink{
-					Tag: cr.GetStatusTagsRaw(),
-				},
-			},
-		*/
 	}
-	//l.Spec.Oda = ndda.Oda
-	//return l
 }
 
-func updateLogicalTopologyLink(cr *topov1alpha1.Link, mhtl *topov1alpha1.Link) *topov1alpha1.Link {
-	/*
-		if cr.GetEndPointAMultiHoming() && mhtl.GetEndPointAMultiHoming() && (cr.GetEndPointAMultiHomingName() == mhtl.GetEndPointAMultiHomingName()) {
-			nodeNameA := cr.GetEndpointANodeName()
-			interfaceNameA := cr.GetLagAName()
-
-			//fmt.Printf("updateLogicalTopologyLink: nodename: %s, itfcename: %s\n", nodeNameA, interfaceNameA)
-
-			found := false
-			for _, tag := range mhtl.GetEndpointATagRaw() {
-				if *tag.Key == nodeNameA && *tag.Value == interfaceNameA {
-					found = true
-					break
-				}
-			}
-			tagNodeName := strings.Join([]string{topov1alpha1.NodePrefix, nodeNameA}, ":")
-			if !found {
-				mhtl.AddEndPointATag(tagNodeName, interfaceNameA)
-			}
+// buildLogicalEndpoint returns the endpoint of the logical link for the endpoint of a
+// member link. A multi-homed endpoint has no node, the member nodes and their lag
+// are tracked in the tags of the endpoint.
+func buildLogicalEndpoint(ep *topov1alpha1.Endpoints) *topov1alpha1.Endpoints {
+	if ep.MultiHoming {
+		return &topov1alpha1.Endpoints{
+			InterfaceName:   ep.MultiHomingName,
+			Kind:            ep.Kind,
+			LacpFallback:    ep.LacpFallback,
+			EndpointGroup:   ep.EndpointGroup,
+			MultiHoming:     true,
+			MultiHomingName: ep.MultiHomingName,
+			Tag:             map[string]string{getNodeTag(ep.NodeName): ep.LagName},
 		}
-		if cr.GetEndPointBMultiHoming() && mhtl.GetEndPointBMultiHoming() && (cr.GetEndPointBMultiHomingName() == mhtl.GetEndPointBMultiHomingName()) {
-			nodeNameB := cr.GetEndpointBNodeName()
-			interfaceNameB := cr.GetLagBName()
-
-			found := false
-			for _, tag := range mhtl.GetEndpointBTagRaw() {
-				if *tag.Key == nodeNameB && *tag.Value == interfaceNameB {
-					found = true
-					break
-				}
-			}
-			tagNodeName := strings.Join([]string{topov1alpha1.NodePrefix, nodeNameB}, ":")
-			if !found {
-				mhtl.AddEndPointBTag(tagNodeName, interfaceNameB)
-			}
-		}
-	*/
-	return mhtl
+	}
+	return &topov1alpha1.Endpoints{
+		NodeName:      ep.NodeName,
+		InterfaceName: ep.LagName,
+		Kind:          ep.Kind,
+		LacpFallback:  ep.LacpFallback,
+		LagName:       ep.LagName,
+		EndpointGroup: ep.EndpointGroup,
+	}
 }
 
-func updateDeleteLogicalTopologyLink(cr *topov1alpha1.Link, mhtl *topov1alpha1.Link) *topov1alpha1.Link {
-	/*
-		if cr.GetEndPointAMultiHoming() && mhtl.GetEndPointAMultiHoming() && (cr.GetEndPointAMultiHomingName() == mhtl.GetEndPointAMultiHomingName()) {
-			interfaceNameA := cr.GetLagAName()
-			tagNodeName := strings.Join([]string{topov1alpha1.NodePrefix, cr.GetEndpointANodeName()}, ":")
+// getNodeTag returns the tag key of a member node of a multi-homed endpoint
+func getNodeTag(nodeName string) string {
+	return strings.Join([]string{topov1alpha1.NodePrefix, nodeName}, ":")
+}
 
-			//fmt.Printf("updateLogicalTopologyLink: nodename: %s, itfcename: %s\n", nodeNameA, interfaceNameA)
+// getTagNode returns the node name of the tag key of a multi-homed endpoint
+func getTagNode(key string) (string, bool) {
+	if !strings.HasPrefix(key, topov1alpha1.NodePrefix+":") {
+		return "", false
+	}
+	return strings.TrimPrefix(key, topov1alpha1.NodePrefix+":"), true
+}
 
-			mhtl.DeleteEndPointATag(tagNodeName, interfaceNameA)
-		}
-		if cr.GetEndPointBMultiHoming() && mhtl.GetEndPointBMultiHoming() && (cr.GetEndPointBMultiHomingName() == mhtl.GetEndPointBMultiHomingName()) {
-			interfaceNameB := cr.GetLagBName()
-			tagNodeName := strings.Join([]string{topov1alpha1.NodePrefix, cr.GetEndpointBNodeName()}, ":")
+// updateLogicalTopologyLink adds the member link to the logical link
+func updateLogicalTopologyLink(cr *topov1alpha1.Link, mhtl *topov1alpha1.Link) *topov1alpha1.Link {
+	meta.AddOwnerReference(mhtl, meta.AsOwner(meta.TypedReferenceTo(cr, topov1alpha1.LinkGroupVersionKind)))
 
-			mhtl.DeleteEndPointBTag(tagNodeName, interfaceNameB)
-		}
-	*/
+	if cr.GetEndPointAMultiHoming() && mhtl.GetEndPointAMultiHoming() && (cr.GetEndPointAMultiHomingName() == mhtl.GetEndPointAMultiHomingName()) {
+		mhtl.AddEndPointATag(getNodeTag(cr.GetEndpointANodeName()), cr.GetLagAName())
+	}
+	if cr.GetEndPointBMultiHoming() && mhtl.GetEndPointBMultiHoming() && (cr.GetEndPointBMultiHomingName() == mhtl.GetEndPointBMultiHomingName()) {
+		mhtl.AddEndPointBTag(getNodeTag(cr.GetEndpointBNodeName()), cr.GetLagBName())
+	}
 	return mhtl
 }
 
-func updateDeleteLogicalTopologyLinkNodeEndpoint(cr *topov1alpha1.Link, i int, nodeName, interfaceName string) *topov1alpha1.Link {
-	/*
-		switch i {
-		case 0:
-			cr.DeleteEndPointATag(nodeName, interfaceName)
-		case 1:
-			cr.DeleteEndPointATag(nodeName, interfaceName)
+// updateDeleteLogicalTopologyLink removes the member link from the logical link
+func updateDeleteLogicalTopologyLink(cr *topov1alpha1.Link, mhtl *topov1alpha1.Link) *topov1alpha1.Link {
+	refs := make([]metav1.OwnerReference, 0, len(mhtl.GetOwnerReferences()))
+	for _, ref := range mhtl.GetOwnerReferences() {
+		if ref.UID != cr.GetUID() {
+			refs = append(refs, ref)
 		}
-	*/
+	}
+	mhtl.SetOwnerReferences(refs)
+
+	if cr.GetEndPointAMultiHoming() && mhtl.GetEndPointAMultiHoming() && (cr.GetEndPointAMultiHomingName() == mhtl.GetEndPointAMultiHomingName()) {
+		mhtl.DeleteEndPointATag(getNodeTag(cr.GetEndpointANodeName()), cr.GetLagAName())
+	}
+	if cr.GetEndPointBMultiHoming() && mhtl.GetEndPointBMultiHoming() && (cr.GetEndPointBMultiHomingName() == mhtl.GetEndPointBMultiHomingName()) {
+		mhtl.DeleteEndPointBTag(getNodeTag(cr.GetEndpointBNodeName()), cr.GetLagBName())
+	}
+	return mhtl
+}
+
+// updateDeleteLogicalTopologyLinkNodeEndpoint removes a member node from endpoint i of the logical link
+func updateDeleteLogicalTopologyLinkNodeEndpoint(cr *topov1alpha1.Link, i int, nodeTag, lagName string) *topov1alpha1.Link {
+	switch i {
+	case 0:
+		cr.DeleteEndPointATag(nodeTag, lagName)
+	case 1:
+		cr.DeleteEndPointBTag(nodeTag, lagName)
+	}
 	return cr
 }
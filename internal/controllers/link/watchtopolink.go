@@ -23,7 +23,6 @@ import (
 	"github.com/yndd/app-runtime/pkg/odns"
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
-	"github.com/yndd/topology/internal/handler"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
@@ -37,7 +36,7 @@ type EnqueueRequestForAllTopologyLinks struct {
 	log    logging.Logger
 	ctx    context.Context
 
-	handler handler.Handler
+	//handler handler.Handler
 
 	//newTopoLinkList func() topov1alpha1.TlList
 }
@@ -88,8 +87,8 @@ func (e *EnqueueRequestForAllTopologyLinks) delete(obj runtime.Object, queue add
 		//if topolink.GetTopologyName() == dd.GetTopologyName() {
 		linkDnsName, _ := odns.Name2OdnsTopo(topolink.GetName()).GetFullOdaName()
 		if linkDnsName == watchDnsName {
-			crName := getCrName(&topolink)
-			e.handler.ResetSpeedy(crName)
+			//crName := getCrName(&topolink)
+			//e.handler.ResetSpeedy(crName)
 			// if a logical link gets deleted, we need to see if there are other member links, so we reconcile
 			// all the links in the topology that are NOT logical links
 			// we get a small delete and add event of the logical link
@@ -102,6 +101,31 @@ func (e *EnqueueRequestForAllTopologyLinks) delete(obj runtime.Object, queue add
 						Name:      topolink.GetName()}})
 				}
 			}
+			// a link that conflicts with the deleted link can become ready
+			if sharesInterface(dd, &topolink) {
+				log.Debug("conflicting link", "name", topolink.GetName())
+				queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: topolink.GetNamespace(),
+					Name:      topolink.GetName()}})
+			}
+		}
+	}
+}
+
+// sharesInterface returns true if the links use the same interface of a node
+func sharesInterface(a, b *topov1alpha1.Link) bool {
+	if a.GetName() == b.GetName() {
+		return false
+	}
+	for _, epA := range a.GetEndpoints() {
+		if epA.NodeName == "" || epA.InterfaceName == "" {
+			continue
+		}
+		for _, epB := range b.GetEndpoints() {
+			if epA.NodeName == epB.NodeName && epA.InterfaceName == epB.InterfaceName {
+				return true
+			}
 		}
 	}
+	return false
 }
@@ -22,7 +22,6 @@ import (
 	"github.com/yndd/app-runtime/pkg/odns"
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
-	"github.com/yndd/topology/internal/handler"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
@@ -36,7 +35,7 @@ type EnqueueRequestForAllTopologyNodes struct {
 	log    logging.Logger
 	ctx    context.Context
 
-	handler handler.Handler
+	//handler handler.Handler
 
 	//newTopoLinkList func() topov1alpha1.TlList
 }
@@ -83,8 +82,8 @@ func (e *EnqueueRequestForAllTopologyNodes) add(obj runtime.Object, queue adder)
 		//if topolink.GetTopologyName() == dd.GetTopologyName() {
 		linkDnsName, _ := odns.Name2OdnsTopo(topolink.GetName()).GetFullOdaName()
 		if linkDnsName == watchDnsName {
-			crName := getCrName(&topolink)
-			e.handler.ResetSpeedy(crName)
+			//crName := getCrName(&topolink)
+			//e.handler.ResetSpeedy(crName)
 
 			queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{
 				Namespace: topolink.GetNamespace(),
@@ -24,7 +24,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/yndd/app-runtime/pkg/odns"
 	"github.com/yndd/app-runtime/pkg/reconciler/managed"
-	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
 	"github.com/yndd/ndd-runtime/pkg/event"
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	"github.com/yndd/ndd-runtime/pkg/resource"
@@ -34,7 +33,6 @@ import (
 
 	"github.com/yndd/ndd-runtime/pkg/shared"
 	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
-	corev1 "k8s.io/api/core/v1"
 )
 
 const (
@@ -195,10 +193,12 @@ func (r *application) handleAppLogic(ctx context.Context, cr *topov1alpha1.Node)
 		//cr.SetReason("topology not found")
 		return nil, errors.Wrap(err, "topology not found")
 	}
-	if topo.GetCondition(nddv1.ConditionKindReady).Status != corev1.ConditionTrue {
+	// the topology readiness reflects its nodes and links, so they only depend on
+	// the topology being reconciled
+	if !topo.IsReconciled() {
 		//cr.SetStatus("down")
 		//cr.SetReason("topology not found or ready")
-		return nil, errors.New("topology not ready")
+		return nil, errors.New("topology not synced")
 	}
 
 	// topology found
@@ -207,7 +207,7 @@ func (r *application) handleAppLogic(ctx context.Context, cr *topov1alpha1.Node)
 		return nil, err
 	}
 
-	if err := r.setPlatform(ctx, cr, topo); err != nil {
+	if err := r.setDefaults(ctx, cr, topo); err != nil {
 		return nil, err
 	}
 	log.Debug("handleAppLogic topologynode set oda")
@@ -232,27 +232,48 @@ func (r *application) handleStatus(ctx context.Context, cr *topov1alpha1.Node, t
 	return nil
 }
 
-func (r *application) setPlatform(ctx context.Context, cr *topov1alpha1.Node, topo *topov1alpha1.Topology) error {
-	/*
-		r.log.Debug("Setflatform", "platform", cr.GetPlatform())
-		if cr.GetPlatform() == "" && cr.GetPosition() != topov1alpha1.NodePositionServer.String() {
-			// platform is not defined at node level
-			p := topo.GetPlatformByKindName(cr.GetKindName())
-			if p != "" {
-				cr.SetPlatform(p)
-				return nil
-			}
-			p = topo.GetPlatformFromDefaults()
-			if p != "" {
-				cr.SetPlatform(p)
-				return nil
-			}
-			// platform is not defined we use the global default
-			cr.SetPlatform("ixrd2")
-			return nil
+// setDefaults records the properties of the node in the status with the defaults of the
+// topology applied. Empty properties are taken from the vendor type info of the topology
+// matching the vendor type of the node, then from the defaults of the topology. The
+// default tags of the topology are merged with the tags of the node.
+func (r *application) setDefaults(ctx context.Context, cr *topov1alpha1.Node, topo *topov1alpha1.Topology) error {
+	p := &topov1alpha1.NodeProperties{}
+	if cr.Spec.Properties != nil {
+		p = cr.Spec.Properties.DeepCopy()
+	}
+	defaults := topo.GetDefaults()
 
-		}
-	*/
-	// all good since the platform is already set
+	if p.VendorType == "" {
+		p.VendorType = defaults.VendorType
+	}
+	if p.Position == "" {
+		p.Position = defaults.Position
+	}
+	vendorTypeInfo := topo.GetVendorTypeInfo(p.VendorType)
+	if p.Platform == "" {
+		p.Platform = getDefault(vendorTypeInfo.Platform, defaults.Platform)
+	}
+	if p.ExpectedSWVersion == "" {
+		p.ExpectedSWVersion = getDefault(vendorTypeInfo.ExpectedSWVersion, defaults.ExpectedSWVersion)
+	}
+
+	tags := topo.GetDefaultsTags()
+	for k, v := range p.Tag {
+		tags[k] = v
+	}
+	p.Tag = tags
+
+	r.log.Debug("setDefaults", "vendorType", p.VendorType, "platform", p.Platform, "expectedSwVersion", p.ExpectedSWVersion)
+	cr.Status.Properties = p
 	return nil
 }
+
+// getDefault returns the first value that is not empty
+func getDefault(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -22,7 +22,6 @@ import (
 	"github.com/yndd/app-runtime/pkg/odns"
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
-	"github.com/yndd/topology/internal/handler"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
@@ -40,7 +39,7 @@ type EnqueueRequestForAllTopologies struct {
 	log    logging.Logger
 	ctx    context.Context
 
-	handler handler.Handler
+	//handler handler.Handler
 
 	//newTopoNodeList func() topov1alpha1.TnList
 }
@@ -88,8 +87,8 @@ func (e *EnqueueRequestForAllTopologies) add(obj runtime.Object, queue adder) {
 		nodeDnsName, _ := odns.Name2OdnsTopo(toponode.GetName()).GetFullOdaName()
 		if nodeDnsName == watchDnsName {
 
-			crName := getCrName(&toponode)
-			e.handler.ResetSpeedy(crName)
+			//crName := getCrName(&toponode)
+			//e.handler.ResetSpeedy(crName)
 
 			queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{
 				Namespace: toponode.GetNamespace(),
@@ -0,0 +1,242 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	"github.com/yndd/ndd-runtime/pkg/event"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/ndd-runtime/pkg/meta"
+	"github.com/yndd/ndd-runtime/pkg/resource"
+	"github.com/yndd/ndd-runtime/pkg/shared"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/fabric"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// timers
+	reconcileTimeout = 1 * time.Minute
+	// errors
+	errGetTemplate  = "cannot get template"
+	errUpdateStatus = "cannot update template status"
+)
+
+// Setup adds a controller that validates templates and publishes the summary
+// of the fabric they render.
+func Setup(mgr ctrl.Manager, nddcopts *shared.NddControllerOptions) error {
+	name := strings.Join([]string{topov1alpha1.Group, strings.ToLower(topov1alpha1.TemplateKind)}, "/")
+
+	r := &Reconciler{
+		client: resource.ClientApplicator{
+			Client:     mgr.GetClient(),
+			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
+		},
+		log:    nddcopts.Logger.WithValues("controller", name),
+		record: event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+	}
+
+	templateHandler := &EnqueueRequestForReferringTemplates{
+		client: mgr.GetClient(),
+		log:    nddcopts.Logger,
+		ctx:    context.Background(),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(nddcopts.Copts).
+		For(&topov1alpha1.Template{}).
+		WithEventFilter(resource.IgnoreUpdateWithoutGenerationChangePredicate()).
+		Watches(&source.Kind{Type: &topov1alpha1.Template{}}, templateHandler).
+		Complete(r)
+}
+
+// A Reconciler validates templates. Templates have no children, so the
+// conditions are set by the reconciler itself such that a template which is
+// not ready reports a precise reason.
+type Reconciler struct {
+	client resource.ClientApplicator
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a template.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	cr := &topov1alpha1.Template{}
+	if err := r.client.Get(ctx, req.NamespacedName, cr); err != nil {
+		// There's no need to requeue if we no longer exist. Otherwise we'll be
+		// requeued implicitly because we return an error.
+		log.Debug(errGetTemplate, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetTemplate)
+	}
+	if meta.WasDeleted(cr) {
+		return reconcile.Result{}, nil
+	}
+
+	summary, reason, err := r.validate(ctx, cr)
+	if err != nil {
+		log.Debug("template not ready", "reason", reason, "error", err)
+		r.record.Event(cr, event.Warning(event.Reason(reason), err))
+		c := topov1alpha1.NotReady().WithMessage(err.Error())
+		c.Reason = reason
+		cr.Status.Summary = nil
+		cr.SetConditions(nddv1.ReconcileSuccess(), c)
+		// references or interface profiles can be created later
+		return reconcile.Result{RequeueAfter: reconcileTimeout}, errors.Wrap(r.client.Status().Update(ctx, cr), errUpdateStatus)
+	}
+
+	cr.Status.Summary = summary
+	cr.SetConditions(nddv1.ReconcileSuccess(), topov1alpha1.Ready())
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cr), errUpdateStatus)
+}
+
+// validate checks the template and its references and computes the summary of
+// the fabric. When the template is not valid the reason is returned with the error.
+func (r *Reconciler) validate(ctx context.Context, cr *topov1alpha1.Template) (*topov1alpha1.TemplateSummary, nddv1.ConditionReason, error) {
+	ft := cr.Spec.Properties.Fabric
+	if ft == nil {
+		return &topov1alpha1.TemplateSummary{}, "", nil
+	}
+
+	child, err := r.isChildTemplate(ctx, cr)
+	if err != nil {
+		return nil, topov1alpha1.ConditionReasonNotReady, err
+	}
+	if child {
+		if err := ft.CheckTemplate(false); err != nil {
+			return nil, topov1alpha1.ConditionReasonInvalidTemplate, err
+		}
+		return summarizePodTemplate(ft), "", nil
+	}
+
+	if err := ft.CheckTemplate(true); err != nil {
+		return nil, topov1alpha1.ConditionReasonInvalidTemplate, err
+	}
+	if err := r.checkReferences(ctx, cr); err != nil {
+		return nil, topov1alpha1.ConditionReasonReferenceNotFound, err
+	}
+
+	profiles, err := fabric.GetInterfaceProfiles(ctx, r.client, cr.GetNamespace())
+	if err != nil {
+		return nil, topov1alpha1.ConditionReasonNotReady, err
+	}
+	f, err := fabric.NewFabric(cr.GetNamespacedName(), ft,
+		fabric.WithLogger(r.log),
+		fabric.WithClient(r.client),
+		fabric.WithInterfaceProfiles(profiles),
+	)
+	if err != nil {
+		return nil, topov1alpha1.ConditionReasonInvalidFabric, err
+	}
+	return summarizeFabric(f), "", nil
+}
+
+// isChildTemplate returns true if the template defines a single pod that is
+// referenced by other templates, either directly or through a definition,
+// or a single pod without pod number which is only valid as child template
+func (r *Reconciler) isChildTemplate(ctx context.Context, cr *topov1alpha1.Template) (bool, error) {
+	ft := cr.Spec.Properties.Fabric
+	if ft.IsChildTemplate() {
+		return true, nil
+	}
+	if ft.HasTier1() || ft.HasBorderLeaf() || len(ft.Pod) != 1 || ft.HasReference() {
+		return false, nil
+	}
+
+	tl := &topov1alpha1.TemplateList{}
+	if err := r.client.List(ctx, tl); err != nil {
+		return false, err
+	}
+	for _, t := range tl.Items {
+		if t.Spec.Properties.Fabric == nil {
+			continue
+		}
+		for _, pod := range t.Spec.Properties.Fabric.Pod {
+			if pod.TemplateReference != nil &&
+				fabric.NamespacedReference(*pod.TemplateReference, t.GetNamespace()) == cr.GetNamespacedName() {
+				return true, nil
+			}
+			if pod.DefinitionReference != nil {
+				td := &topov1alpha1.Definition{}
+				name, namespace := meta.NamespacedName(fabric.NamespacedReference(*pod.DefinitionReference, t.GetNamespace())).GetNameAndNamespace()
+				if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, td); err != nil {
+					continue
+				}
+				if td.Spec.Properties == nil {
+					continue
+				}
+				for _, dt := range td.Spec.Properties.Templates {
+					if fabric.NamespacedReference(dt.NamespacedName, td.GetNamespace()) == cr.GetNamespacedName() {
+						return true, nil
+					}
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// checkReferences validates the template and definition references of the pods exist
+func (r *Reconciler) checkReferences(ctx context.Context, cr *topov1alpha1.Template) error {
+	for _, pod := range cr.Spec.Properties.Fabric.Pod {
+		if pod.TemplateReference != nil {
+			ref := fabric.NamespacedReference(*pod.TemplateReference, cr.GetNamespace())
+			name, namespace := meta.NamespacedName(ref).GetNameAndNamespace()
+			if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &topov1alpha1.Template{}); err != nil {
+				if resource.IgnoreNotFound(err) == nil {
+					return fmt.Errorf("template reference %s not found", ref)
+				}
+				return err
+			}
+		}
+		if pod.DefinitionReference != nil {
+			ref := fabric.NamespacedReference(*pod.DefinitionReference, cr.GetNamespace())
+			name, namespace := meta.NamespacedName(ref).GetNameAndNamespace()
+			td := &topov1alpha1.Definition{}
+			if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, td); err != nil {
+				if resource.IgnoreNotFound(err) == nil {
+					return fmt.Errorf("definition reference %s not found", ref)
+				}
+				return err
+			}
+			if td.Spec.Properties == nil || len(td.Spec.Properties.Templates) != 1 {
+				return fmt.Errorf("definition reference %s must have exactly 1 template", ref)
+			}
+			tmplRef := fabric.NamespacedReference(td.Spec.Properties.Templates[0].NamespacedName, td.GetNamespace())
+			name, namespace = meta.NamespacedName(tmplRef).GetNameAndNamespace()
+			if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &topov1alpha1.Template{}); err != nil {
+				if resource.IgnoreNotFound(err) == nil {
+					return fmt.Errorf("template %s of definition reference %s not found", tmplRef, ref)
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"sort"
+
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/fabric"
+)
+
+// summaryPositions is the order in which the tiers are reported in the summary
+var summaryPositions = []topov1alpha1.Position{
+	topov1alpha1.PositionSuperspine,
+	topov1alpha1.PositionBorderLeaf,
+	topov1alpha1.PositionSpine,
+	topov1alpha1.PositionLeaf,
+}
+
+type platformKey struct {
+	vendorType targetv1.VendorType
+	platform   string
+}
+
+// summarizeFabric returns the summary of a rendered fabric
+func summarizeFabric(f fabric.Fabric) *topov1alpha1.TemplateSummary {
+	pods := map[uint32]struct{}{}
+	tiers := map[topov1alpha1.Position]uint32{}
+	platforms := map[platformKey]*topov1alpha1.TemplateSummaryPlatform{}
+	for _, n := range f.GetFabricNodes() {
+		tiers[n.GetPosition()]++
+		if n.GetPosition() == topov1alpha1.PositionLeaf || n.GetPosition() == topov1alpha1.PositionSpine {
+			pods[n.GetPodIndex()] = struct{}{}
+		}
+
+		k := platformKey{vendorType: n.GetVendorType(), platform: n.GetPlatform()}
+		p, ok := platforms[k]
+		if !ok {
+			p = &topov1alpha1.TemplateSummaryPlatform{
+				VendorType: n.GetVendorType(),
+				Platform:   n.GetPlatform(),
+			}
+			platforms[k] = p
+		}
+		p.Nodes++
+		if n.GetMaxPort() > p.RequiredPorts {
+			p.RequiredPorts = n.GetMaxPort()
+		}
+	}
+
+	// logical lag links are not cabled
+	var links uint32
+	for _, l := range f.GetFabricLinks() {
+		if !l.GetLag() {
+			links++
+		}
+	}
+
+	s := &topov1alpha1.TemplateSummary{
+		Pods:      uint32(len(pods)),
+		Nodes:     getSummaryTiers(tiers),
+		Links:     links,
+		Platforms: make([]*topov1alpha1.TemplateSummaryPlatform, 0, len(platforms)),
+	}
+	for _, p := range platforms {
+		s.Platforms = append(s.Platforms, p)
+	}
+	sort.Slice(s.Platforms, func(i, j int) bool {
+		if s.Platforms[i].VendorType != s.Platforms[j].VendorType {
+			return s.Platforms[i].VendorType < s.Platforms[j].VendorType
+		}
+		return s.Platforms[i].Platform < s.Platforms[j].Platform
+	})
+	return s
+}
+
+// summarizePodTemplate returns the summary of a child template, which defines
+// a single pod; the links and ports depend on the template referring to it
+func summarizePodTemplate(ft *topov1alpha1.FabricTemplate) *topov1alpha1.TemplateSummary {
+	if len(ft.Pod) == 0 {
+		return &topov1alpha1.TemplateSummary{}
+	}
+	tiers := map[topov1alpha1.Position]uint32{}
+	if ft.Pod[0].Tier2 != nil {
+		tiers[topov1alpha1.PositionSpine] = ft.Pod[0].Tier2.NodeNumber
+	}
+	if ft.Pod[0].Tier3 != nil {
+		tiers[topov1alpha1.PositionLeaf] = ft.Pod[0].Tier3.NodeNumber
+	}
+	return &topov1alpha1.TemplateSummary{
+		Pods:  1,
+		Nodes: getSummaryTiers(tiers),
+	}
+}
+
+func getSummaryTiers(tiers map[topov1alpha1.Position]uint32) []*topov1alpha1.TemplateSummaryTier {
+	st := []*topov1alpha1.TemplateSummaryTier{}
+	for _, pos := range summaryPositions {
+		if tiers[pos] > 0 {
+			st = append(st, &topov1alpha1.TemplateSummaryTier{Position: pos, Nodes: tiers[pos]})
+		}
+	}
+	return st
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"context"
+
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/fabric"
+)
+
+type adder interface {
+	Add(item interface{})
+}
+
+type EnqueueRequestForReferringTemplates struct {
+	client client.Client
+	log    logging.Logger
+	ctx    context.Context
+}
+
+// Create enqueues a request for all templates which refer to the template.
+func (e *EnqueueRequestForReferringTemplates) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Update enqueues a request for all templates which refer to the template.
+func (e *EnqueueRequestForReferringTemplates) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.ObjectOld, q)
+	e.add(evt.ObjectNew, q)
+}
+
+// Delete enqueues a request for all templates which refer to the template.
+func (e *EnqueueRequestForReferringTemplates) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Generic enqueues a request for all templates which refer to the template.
+func (e *EnqueueRequestForReferringTemplates) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+func (e *EnqueueRequestForReferringTemplates) add(obj runtime.Object, queue adder) {
+	cr, ok := obj.(*topov1alpha1.Template)
+	if !ok {
+		return
+	}
+	log := e.log.WithValues("event handler", "Template", "namespace", cr.GetNamespace(), "name", cr.GetName())
+	log.Debug("handleEvent")
+
+	tl := &topov1alpha1.TemplateList{}
+	if err := e.client.List(e.ctx, tl); err != nil {
+		log.Debug("cannot get template list", "error", err)
+		return
+	}
+
+	// definitions using the template, a template can refer to them with a definition reference
+	tdl := &topov1alpha1.DefinitionList{}
+	if err := e.client.List(e.ctx, tdl); err != nil {
+		log.Debug("cannot get topology definition list", "error", err)
+		return
+	}
+	refs := map[string]struct{}{}
+	for _, td := range tdl.Items {
+		if td.Spec.Properties == nil {
+			continue
+		}
+		for _, dt := range td.Spec.Properties.Templates {
+			if fabric.NamespacedReference(dt.NamespacedName, td.GetNamespace()) == cr.GetNamespacedName() {
+				refs[td.GetNamespacedName()] = struct{}{}
+			}
+		}
+	}
+
+	// child templates cannot have children themselves so the referring templates
+	// are the only templates affected by the change
+	for _, t := range tl.Items {
+		if t.Spec.Properties.Fabric == nil {
+			continue
+		}
+		for _, pod := range t.Spec.Properties.Fabric.Pod {
+			referring := false
+			if pod.TemplateReference != nil &&
+				fabric.NamespacedReference(*pod.TemplateReference, t.GetNamespace()) == cr.GetNamespacedName() {
+				referring = true
+			}
+			if pod.DefinitionReference != nil {
+				if _, ok := refs[fabric.NamespacedReference(*pod.DefinitionReference, t.GetNamespace())]; ok {
+					referring = true
+				}
+			}
+			if referring {
+				log.Debug("enqueue template", "template", t.GetNamespacedName())
+				queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: t.GetNamespace(),
+					Name:      t.GetName()}})
+				break
+			}
+		}
+	}
+}
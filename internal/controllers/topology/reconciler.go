@@ -18,36 +18,40 @@ package topology
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/yndd/app-runtime/pkg/reconciler/managed"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
 	"github.com/yndd/ndd-runtime/pkg/event"
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	"github.com/yndd/ndd-runtime/pkg/resource"
-	ctrl "sigs.k8s.io/controller-runtime"
-
-	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
-
-	//"github.com/yndd/topology/internal/shared"
 	"github.com/yndd/ndd-runtime/pkg/shared"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
 	// timers
 	reconcileTimeout = 1 * time.Minute
-	veryShortWait    = 1 * time.Second
+	// max number of members reported as not ready
+	maxNotReadyMembers = 50
 	// errors
-	errUnexpectedResource = "unexpected organization object"
-	errGetK8sResource     = "cannot get organization resource"
+	errUnexpectedResource = "unexpected topology object"
+	errListNodes          = "cannot list nodes of topology"
+	errListLinks          = "cannot list links of topology"
 )
 
-// Setup adds a controller that reconciles infra.
+// Setup adds a controller that reconciles topologies.
 func Setup(mgr ctrl.Manager, nddopts *shared.NddControllerOptions) error {
 	name := "nddo/" + strings.ToLower(topov1alpha1.TopologyGroupKind)
-	//topofn := func() topov1alpha1.Tp { return &topov1alpha1.Topology{} }
-	//dpfn := func() orgv1alpha1.Dp { return &orgv1alpha1.Deployment{} }
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(topov1alpha1.TopologyGroupVersionKind),
@@ -58,51 +62,32 @@ func Setup(mgr ctrl.Manager, nddopts *shared.NddControllerOptions) error {
 				Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
 			},
 			log: nddopts.Logger.WithValues("applogic", name),
-			//newTopology:   topofn,
-			//newDeployment: dpfn,
-			//handler:       nddopts.Handler,
 		}),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	)
 
+	memberHandler := &EnqueueRequestForTopologyMembers{
+		log: nddopts.Logger,
+	}
+
+	// the members change their status without changing their generation, so the
+	// generation predicate only applies to the topologies
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(nddopts.Copts).
-		For(&topov1alpha1.Topology{}).
-		Owns(&topov1alpha1.Topology{}).
-		WithEventFilter(resource.IgnoreUpdateWithoutGenerationChangePredicate()).
+		For(&topov1alpha1.Topology{}, builder.WithPredicates(resource.IgnoreUpdateWithoutGenerationChangePredicate())).
+		Owns(&topov1alpha1.Topology{}, builder.WithPredicates(resource.IgnoreUpdateWithoutGenerationChangePredicate())).
+		Watches(&source.Kind{Type: &topov1alpha1.Node{}}, memberHandler).
+		Watches(&source.Kind{Type: &topov1alpha1.Link{}}, memberHandler).
 		Complete(r)
 }
 
 type application struct {
 	client resource.ClientApplicator
 	log    logging.Logger
-
-	//newTopology   func() topov1alpha1.Tp
-	//newDeployment func() orgv1alpha1.Dp
-
-	//handler handler.Handler
-}
-
-/*
-func getCrName(cr *topov1alpha1.Topology) string {
-	return strings.Join([]string{cr.GetNamespace(), cr.GetName()}, ".")
 }
-*/
 
 func (r *application) Initialize(ctx context.Context, mr resource.Managed) error {
-	/*
-		cr, ok := mr.(*topov1alpha1.Topology)
-		if !ok {
-			return errors.New(errUnexpectedResource)
-		}
-
-		if err := cr.InitializeResource(); err != nil {
-			r.log.Debug("Cannot initialize", "error", err)
-			return err
-		}
-	*/
-
 	return nil
 }
 
@@ -119,7 +104,6 @@ func (r *application) FinalUpdate(ctx context.Context, mr resource.Managed) {
 }
 
 func (r *application) Timeout(ctx context.Context, mr resource.Managed) time.Duration {
-
 	return reconcileTimeout
 }
 
@@ -128,53 +112,181 @@ func (r *application) Delete(ctx context.Context, mr resource.Managed) (bool, er
 }
 
 func (r *application) FinalDelete(ctx context.Context, mr resource.Managed) {
-	/*
-		cr, ok := mr.(*topov1alpha1.Topology)
-		if !ok {
-			return
-		}
-		crName := getCrName(cr)
-		r.handler.Delete(crName)
-	*/
 }
 
+// handleAppLogic aggregates the state of the nodes and links of the topology. The
+// topology is healthy when all its infra nodes and links are ready, the nodes and
+// links only depend on the topology being reconciled. A degraded topology fails the
+// update with the infra members which are not ready.
 func (r *application) handleAppLogic(ctx context.Context, cr *topov1alpha1.Topology) (map[string]string, error) {
 	log := r.log.WithValues("function", "handleAppLogic", "crname", cr.GetName())
 	log.Debug("handleAppLogic")
 
-	// initialize speedy
-	//crName := getCrName(cr)
-	//r.handler.Init(crName)
-
-	// get the deployment
-
-	/*
-		odaname, odakind := odns.Name2OdnsTopo(cr.GetName()).GetFullOdaName()
-		log.Debug("odaInfo", "odakind", odakind, "odaname", odaname)
-		if odakind.String() == nddv1.OdaKindDeployment.String() {
-			dep := r.newDeployment()
-			if err := r.client.Get(ctx, types.NamespacedName{
-				Namespace: cr.GetNamespace(),
-				Name:      odaname}, dep); err != nil {
-				// can happen when the deployment is not found
-				//cr.SetStatus("down")
-				//cr.SetReason("organization/deployment not found")
-				return nil, errors.Wrap(err, "organization/deployment not found")
-			}
-			if dep.GetCondition(topov1alpha1.ConditionKindReady).Status != corev1.ConditionTrue {
-				//cr.SetStatus("down")
-				//cr.SetReason("organization/deployment not ready")
-				return nil, errors.New("organization/deployment not ready")
-			}
-		} else {
-			return nil, fmt.Errorf("topo not yet supported w/o deployment: odaName: %s, odaKind: %s", odaname, odakind)
-		}
-	*/
-
 	cr.SetOrganization(cr.GetOrganization())
 	cr.SetDeployment(cr.GetDeployment())
 	cr.SetAvailabilityZone(cr.GetAvailabilityZone())
 	cr.SetResourceName(cr.GetTopologyName())
 
-	return make(map[string]string), nil
+	members, degraded, err := r.getMembers(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	cr.Status.Members = members
+
+	if len(degraded) == 0 {
+		cr.SetConditions(topov1alpha1.Healthy())
+		return make(map[string]string), nil
+	}
+
+	names := make([]string, 0, len(degraded))
+	for name := range degraded {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msg := fmt.Sprintf("infra members not ready: %s", strings.Join(names, ", "))
+	log.Debug("topology degraded", "members", names)
+	// the managed reconciler marks the topology available when the update succeeds,
+	// a degraded topology is reported as an error such that it is not ready
+	cr.SetConditions(topov1alpha1.Unhealthy(topov1alpha1.ConditionReasonDegraded).WithMessage(msg))
+	return nil, errors.New(msg)
+}
+
+// getMembers lists the nodes and links labeled with the topology and counts them
+// per position and kind. The infra members which are not ready are returned with
+// the reason they are not ready such that the topology can report it is degraded.
+func (r *application) getMembers(ctx context.Context, cr *topov1alpha1.Topology) (*topov1alpha1.TopologyMembers, map[string]string, error) {
+	opts := []client.ListOption{
+		client.InNamespace(cr.GetNamespace()),
+		client.MatchingLabels{
+			topov1alpha1.LabelKeyOrganization:     cr.GetOrganization(),
+			topov1alpha1.LabelKeyDeployment:       cr.GetDeployment(),
+			topov1alpha1.LabelKeyAvailabilityZone: cr.GetAvailabilityZone(),
+			topov1alpha1.LabelKeyTopology:         cr.GetTopologyName(),
+		},
+	}
+
+	nl := &topov1alpha1.NodeList{}
+	if err := r.client.List(ctx, nl, opts...); err != nil {
+		return nil, nil, errors.Wrap(err, errListNodes)
+	}
+	ll := &topov1alpha1.LinkList{}
+	if err := r.client.List(ctx, ll, opts...); err != nil {
+		return nil, nil, errors.Wrap(err, errListLinks)
+	}
+
+	members := &topov1alpha1.TopologyMembers{}
+	degraded := map[string]string{}
+	nodes := newMemberCounter()
+	for _, n := range nl.Items {
+		n := n
+		position := getNodePosition(&n)
+		ready, reason := isReady(&n)
+		nodes.count(string(position), ready)
+		if ready {
+			continue
+		}
+		members.NotReady = appendNotReady(members.NotReady, topov1alpha1.NodeKind, n.GetName(), reason)
+		if position != topov1alpha1.PositionServer {
+			degraded[n.GetName()] = reason
+		}
+	}
+	links := newMemberCounter()
+	for _, l := range ll.Items {
+		l := l
+		kind := topov1alpha1.LinkKindInfra
+		if l.Spec.Properties != nil && l.Spec.Properties.Kind != "" {
+			kind = l.Spec.Properties.Kind
+		}
+		ready, reason := isReady(&l)
+		links.count(string(kind), ready)
+		if ready {
+			continue
+		}
+		members.NotReady = appendNotReady(members.NotReady, topov1alpha1.LinkKind, l.GetName(), reason)
+		if kind == topov1alpha1.LinkKindInfra {
+			degraded[l.GetName()] = reason
+		}
+	}
+	members.Nodes = nodes.get()
+	members.Links = links.get()
+	return members, degraded, nil
+}
+
+// getNodePosition returns the position of the node with the topology defaults applied,
+// falling back to the position in the spec when the node is not reconciled yet
+func getNodePosition(n *topov1alpha1.Node) topov1alpha1.Position {
+	if n.Status.Properties != nil && n.Status.Properties.Position != "" {
+		return n.Status.Properties.Position
+	}
+	if n.Spec.Properties != nil && n.Spec.Properties.Position != "" {
+		return n.Spec.Properties.Position
+	}
+	return topov1alpha1.PositionUnknown
+}
+
+// isReady returns true if the member is ready and healthy, otherwise the reason it
+// is not ready
+func isReady(mr resource.Conditioned) (bool, string) {
+	ready := mr.GetCondition(nddv1.ConditionKindReady)
+	healthy := mr.GetCondition(topov1alpha1.ConditionKindHealthy)
+	if ready.Status == corev1.ConditionTrue {
+		if healthy.Status != corev1.ConditionFalse {
+			return true, ""
+		}
+		if healthy.Message != "" {
+			return false, healthy.Message
+		}
+		return false, string(healthy.Reason)
+	}
+	// the managed reconciler reports the error in the synced condition
+	if synced := mr.GetCondition(nddv1.ConditionKindSynced); synced.Status == corev1.ConditionFalse && synced.Message != "" {
+		return false, synced.Message
+	}
+	if ready.Message != "" {
+		return false, ready.Message
+	}
+	return false, string(ready.Reason)
+}
+
+func appendNotReady(notReady []*topov1alpha1.TopologyMember, kind, name, reason string) []*topov1alpha1.TopologyMember {
+	if len(notReady) >= maxNotReadyMembers {
+		return notReady
+	}
+	return append(notReady, &topov1alpha1.TopologyMember{
+		Kind:   kind,
+		Name:   name,
+		Reason: reason,
+	})
+}
+
+type memberCounter struct {
+	counts map[string]*topov1alpha1.TopologyMemberCount
+}
+
+func newMemberCounter() *memberCounter {
+	return &memberCounter{
+		counts: map[string]*topov1alpha1.TopologyMemberCount{},
+	}
+}
+
+func (c *memberCounter) count(name string, ready bool) {
+	if _, ok := c.counts[name]; !ok {
+		c.counts[name] = &topov1alpha1.TopologyMemberCount{Name: name}
+	}
+	c.counts[name].Total++
+	if ready {
+		c.counts[name].Ready++
+	}
+}
+
+// get returns the counts sorted by name such that the status is stable
+func (c *memberCounter) get() []*topov1alpha1.TopologyMemberCount {
+	counts := make([]*topov1alpha1.TopologyMemberCount, 0, len(c.counts))
+	for _, mc := range c.counts {
+		counts = append(counts, mc)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
 }
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"strings"
+
+	"github.com/yndd/app-runtime/pkg/odns"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/ndd-runtime/pkg/resource"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type adder interface {
+	Add(item interface{})
+}
+
+// EnqueueRequestForTopologyMembers enqueues the topology of a node or link.
+type EnqueueRequestForTopologyMembers struct {
+	log logging.Logger
+}
+
+// Create enqueues a request for the topology of the node or link.
+func (e *EnqueueRequestForTopologyMembers) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Update enqueues a request for the topology of the node or link when its
+// readiness or labels changed.
+func (e *EnqueueRequestForTopologyMembers) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if !memberChanged(evt.ObjectOld, evt.ObjectNew) {
+		return
+	}
+	e.add(evt.ObjectOld, q)
+	e.add(evt.ObjectNew, q)
+}
+
+// Delete enqueues a request for the topology of the node or link.
+func (e *EnqueueRequestForTopologyMembers) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+// Generic enqueues a request for the topology of the node or link.
+func (e *EnqueueRequestForTopologyMembers) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, q)
+}
+
+func (e *EnqueueRequestForTopologyMembers) add(obj client.Object, queue adder) {
+	switch obj.(type) {
+	case *topov1alpha1.Node, *topov1alpha1.Link:
+	default:
+		return
+	}
+	topoName, ok := getTopologyName(obj)
+	if !ok {
+		return
+	}
+	log := e.log.WithValues("event handler", "TopologyMember", "namespace", obj.GetNamespace(), "name", obj.GetName())
+	log.Debug("handleEvent", "topology", topoName)
+
+	queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      topoName}})
+}
+
+// getTopologyName returns the name of the topology of the member from its labels:
+// <org>.<dep>.<az>.<topology name>
+func getTopologyName(obj client.Object) (string, bool) {
+	labels := obj.GetLabels()
+	topoName, ok := labels[topov1alpha1.LabelKeyTopology]
+	if !ok || topoName == "" {
+		return "", false
+	}
+	o := &odns.Odns{
+		Organization:     labels[topov1alpha1.LabelKeyOrganization],
+		Deployment:       labels[topov1alpha1.LabelKeyDeployment],
+		AvailabilityZone: labels[topov1alpha1.LabelKeyAvailabilityZone],
+	}
+	odaName, _ := o.GetFullOdaName()
+	if odaName == "" {
+		return topoName, true
+	}
+	return strings.Join([]string{odaName, topoName}, "."), true
+}
+
+// memberChanged returns true if the change of the member affects the topology status
+func memberChanged(oldObj, newObj client.Object) bool {
+	oldTopoName, _ := getTopologyName(oldObj)
+	newTopoName, _ := getTopologyName(newObj)
+	if oldTopoName != newTopoName {
+		return true
+	}
+	if oldObj.GetGeneration() != newObj.GetGeneration() {
+		return true
+	}
+	o, ok := oldObj.(resource.Conditioned)
+	if !ok {
+		return true
+	}
+	n, ok := newObj.(resource.Conditioned)
+	if !ok {
+		return true
+	}
+	for _, ck := range []nddv1.ConditionKind{nddv1.ConditionKindReady, nddv1.ConditionKindSynced, topov1alpha1.ConditionKindHealthy} {
+		if !o.GetCondition(ck).Equal(n.GetCondition(ck)) {
+			return true
+		}
+	}
+	return false
+}
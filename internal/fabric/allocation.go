@@ -0,0 +1,218 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+)
+
+// allocator keeps the pod and interface indexes of a previous render of the fabric
+// such that they are reused when the fabric is rendered again. Indexes of the previous
+// render are reserved, new pods and links get free indexes.
+// +k8s:deepcopy-gen=false
+type allocator struct {
+	prevPods  map[string]uint32
+	prevLinks map[linkKey]*topov1alpha1.LinkAllocation
+	// borderleaf allocations per node name
+	prevBorderLeafs map[string]*topov1alpha1.BorderLeafAllocation
+	// used interface indexes per node name
+	used map[string]map[uint32]struct{}
+	// used borderleaf indexes per pod index, pod index 0 are the superspines
+	usedBorderLeafs map[uint32]map[uint32]struct{}
+
+	pods        []*topov1alpha1.PodAllocation
+	links       []*topov1alpha1.LinkAllocation
+	borderLeafs []*topov1alpha1.BorderLeafAllocation
+}
+
+type linkKey struct {
+	nodeA  string
+	nodeB  string
+	uplink uint32
+}
+
+func newAllocator(prev *topov1alpha1.FabricAllocations) *allocator {
+	a := &allocator{
+		prevPods:        map[string]uint32{},
+		prevLinks:       map[linkKey]*topov1alpha1.LinkAllocation{},
+		prevBorderLeafs: map[string]*topov1alpha1.BorderLeafAllocation{},
+		used:            map[string]map[uint32]struct{}{},
+		usedBorderLeafs: map[uint32]map[uint32]struct{}{},
+		pods:            make([]*topov1alpha1.PodAllocation, 0),
+		links:           make([]*topov1alpha1.LinkAllocation, 0),
+		borderLeafs:     make([]*topov1alpha1.BorderLeafAllocation, 0),
+	}
+	if prev == nil {
+		return a
+	}
+	for _, pa := range prev.Pods {
+		a.prevPods[pa.PodTemplate] = pa.PodIndex
+	}
+	for _, la := range prev.Links {
+		a.prevLinks[linkKey{nodeA: la.NodeA, nodeB: la.NodeB, uplink: la.Uplink}] = la
+		a.use(la.NodeA, la.IndexA)
+		a.use(la.NodeB, la.IndexB)
+	}
+	for _, ba := range prev.BorderLeafs {
+		a.prevBorderLeafs[ba.NodeName] = ba
+		a.useBorderLeafIndex(ba.PodIndex, ba.Index)
+	}
+	return a
+}
+
+func (a *allocator) use(nodeName string, idx uint32) {
+	if _, ok := a.used[nodeName]; !ok {
+		a.used[nodeName] = map[uint32]struct{}{}
+	}
+	a.used[nodeName][idx] = struct{}{}
+}
+
+func (a *allocator) isUsed(nodeName string, idx uint32) bool {
+	_, ok := a.used[nodeName][idx]
+	return ok
+}
+
+// allocateIndex returns the index if it is free on the node, otherwise the
+// next free index after it
+func (a *allocator) allocateIndex(nodeName string, idx uint32) uint32 {
+	for a.isUsed(nodeName, idx) {
+		idx++
+	}
+	a.use(nodeName, idx)
+	return idx
+}
+
+func (a *allocator) useBorderLeafIndex(podIndex, idx uint32) {
+	if _, ok := a.usedBorderLeafs[podIndex]; !ok {
+		a.usedBorderLeafs[podIndex] = map[uint32]struct{}{}
+	}
+	a.usedBorderLeafs[podIndex][idx] = struct{}{}
+}
+
+// allocateBorderLeafIndex returns the index of the borderleaf on the spines of the pod or on
+// the superspines when the pod index is 0. A borderleaf of the previous render connected to
+// the same pod keeps its index, such that adding pods or leafs does not re-index its uplinks.
+// A new borderleaf gets the computed index unless it is allocated to another borderleaf.
+func (a *allocator) allocateBorderLeafIndex(nodeName string, podIndex, idx uint32) uint32 {
+	if ba, ok := a.prevBorderLeafs[nodeName]; ok && ba.PodIndex == podIndex {
+		idx = ba.Index
+		// a borderleaf is only allocated once
+		delete(a.prevBorderLeafs, nodeName)
+	} else {
+		for {
+			if _, ok := a.usedBorderLeafs[podIndex][idx]; !ok {
+				break
+			}
+			idx++
+		}
+		a.useBorderLeafIndex(podIndex, idx)
+	}
+	a.borderLeafs = append(a.borderLeafs, &topov1alpha1.BorderLeafAllocation{
+		NodeName: nodeName,
+		PodIndex: podIndex,
+		Index:    idx,
+	})
+	return idx
+}
+
+// allocatePodIndexes returns the first pod index per pod template. Explicit pod indexes
+// take precedence, pod templates without pod index reuse the pod index of the previous
+// render if the range is still free, the others get the lowest free range.
+func (a *allocator) allocatePodIndexes(template *topov1alpha1.FabricTemplate, podKeys []string) ([]uint32, error) {
+	t := template.DeepCopy()
+	used := map[uint32]struct{}{}
+	for _, pod := range t.Pod {
+		if pod.PodIndex == nil {
+			continue
+		}
+		for i := uint32(0); i < pod.GetPodNumber(); i++ {
+			used[*pod.PodIndex+i] = struct{}{}
+		}
+	}
+	for p, pod := range t.Pod {
+		if pod.PodIndex != nil || p >= len(podKeys) {
+			continue
+		}
+		podIndex, ok := a.prevPods[podKeys[p]]
+		if !ok || !isRangeFree(used, podIndex, pod.GetPodNumber()) {
+			continue
+		}
+		pod.PodIndex = &podIndex
+		for i := uint32(0); i < pod.GetPodNumber(); i++ {
+			used[podIndex+i] = struct{}{}
+		}
+	}
+
+	podIndexes, err := t.GetPodIndexes()
+	if err != nil {
+		return nil, err
+	}
+	for p, podIndex := range podIndexes {
+		if p < len(podKeys) {
+			a.pods = append(a.pods, &topov1alpha1.PodAllocation{
+				PodTemplate: podKeys[p],
+				PodIndex:    podIndex,
+			})
+		}
+	}
+	return podIndexes, nil
+}
+
+func isRangeFree(used map[uint32]struct{}, start, num uint32) bool {
+	for i := uint32(0); i < num; i++ {
+		if _, ok := used[start+i]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// allocateLink returns the link between the nodes, idxA and idxB are the interface
+// indexes computed for the link. A link of the previous render keeps its indexes, a
+// new link uses the computed indexes unless they are allocated to another link.
+func (a *allocator) allocateLink(nodeA FabricNode, idxA uint32, nodeB FabricNode, idxB uint32, uplink uint32) FabricLink {
+	k := linkKey{nodeA: nodeA.GetNodeName(), nodeB: nodeB.GetNodeName(), uplink: uplink}
+	if la, ok := a.prevLinks[k]; ok {
+		idxA = la.IndexA
+		idxB = la.IndexB
+		// a link is only allocated once
+		delete(a.prevLinks, k)
+	} else {
+		idxA = a.allocateIndex(k.nodeA, idxA)
+		idxB = a.allocateIndex(k.nodeB, idxB)
+	}
+	a.links = append(a.links, &topov1alpha1.LinkAllocation{
+		NodeA:  k.nodeA,
+		IndexA: idxA,
+		NodeB:  k.nodeB,
+		IndexB: idxB,
+		Uplink: uplink,
+	})
+	return NewFabricLink(
+		&Endpoint{Node: nodeA, IfName: nodeA.GetInterfaceName(idxA), Index: idxA},
+		&Endpoint{Node: nodeB, IfName: nodeB.GetInterfaceName(idxB), Index: idxB},
+	)
+}
+
+// getAllocations returns the indexes allocated by the render of the fabric
+func (a *allocator) getAllocations() *topov1alpha1.FabricAllocations {
+	return &topov1alpha1.FabricAllocations{
+		Pods:        a.pods,
+		Links:       a.links,
+		BorderLeafs: a.borderLeafs,
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+)
+
+func uint32Ptr(i uint32) *uint32 { return &i }
+
+var (
+	leafVendorInfo = []*topov1alpha1.FabricTierVendorInfo{
+		{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D2"},
+	}
+	spineVendorInfo = []*topov1alpha1.FabricTierVendorInfo{
+		{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D3"},
+	}
+)
+
+type podTemplateModifier func(p *topov1alpha1.PodTemplate)
+
+func withPodName(name string) podTemplateModifier {
+	return func(p *topov1alpha1.PodTemplate) { p.Name = name }
+}
+
+func withPodNumber(num uint32) podTemplateModifier {
+	return func(p *topov1alpha1.PodTemplate) { p.PodNumber = uint32Ptr(num) }
+}
+
+func withLeafs(num, uplinksPerNode uint32) podTemplateModifier {
+	return func(p *topov1alpha1.PodTemplate) {
+		p.Tier3.NodeNumber = num
+		p.Tier3.UplinksPerNode = uplinksPerNode
+	}
+}
+
+func podTemplate(m ...podTemplateModifier) *topov1alpha1.PodTemplate {
+	p := &topov1alpha1.PodTemplate{
+		PodNumber: uint32Ptr(1),
+		Tier2:     &topov1alpha1.TierTemplate{NodeNumber: 2, VendorInfo: spineVendorInfo},
+		Tier3:     &topov1alpha1.TierTemplate{NodeNumber: 2, VendorInfo: leafVendorInfo},
+	}
+	for _, f := range m {
+		f(p)
+	}
+	return p
+}
+
+func fabricTemplate(maxUplinks uint32, pods ...*topov1alpha1.PodTemplate) *topov1alpha1.FabricTemplate {
+	return &topov1alpha1.FabricTemplate{
+		MaxUplinksTier2ToTier1: maxUplinks,
+		MaxUplinksTier3ToTier2: maxUplinks,
+		Pod:                    pods,
+	}
+}
+
+// getLinkNames returns the names of the links of the fabric, the name of a link
+// holds the nodes and interfaces of its endpoints
+func getLinkNames(f Fabric) []string {
+	names := make([]string, 0)
+	for _, l := range f.GetFabricLinks() {
+		names = append(names, l.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getNodeNames(f Fabric) []string {
+	names := make([]string, 0)
+	for _, n := range f.GetFabricNodes() {
+		names = append(names, n.GetNodeName())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestAllocationReuse(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		from   *topov1alpha1.FabricTemplate
+		to     *topov1alpha1.FabricTemplate
+	}{
+		"AddPod": {
+			reason: "The links of the existing pod should keep their interfaces when a pod is added.",
+			from:   fabricTemplate(2, podTemplate()),
+			to:     fabricTemplate(2, podTemplate(withPodNumber(2))),
+		},
+		"AddPodTemplate": {
+			reason: "The links of the existing pods should keep their interfaces when a pod template is added.",
+			from:   fabricTemplate(2, podTemplate(withPodName("a"))),
+			to:     fabricTemplate(2, podTemplate(withPodName("b"), withLeafs(3, 1)), podTemplate(withPodName("a"))),
+		},
+		"AddLeaf": {
+			reason: "The links of the existing leafs should keep their interfaces when a leaf is added.",
+			from:   fabricTemplate(2, podTemplate()),
+			to:     fabricTemplate(2, podTemplate(withLeafs(3, 1))),
+		},
+		"AddUplink": {
+			reason: "The existing uplinks should keep their interfaces when the uplinks per node increase.",
+			from:   fabricTemplate(2, podTemplate(withLeafs(2, 1))),
+			to:     fabricTemplate(2, podTemplate(withLeafs(2, 2))),
+		},
+		"IncreaseMaxUplinks": {
+			reason: "The existing uplinks should keep their interfaces when the max uplinks change the computed indexes.",
+			from:   fabricTemplate(1, podTemplate(withLeafs(2, 1))),
+			to:     fabricTemplate(2, podTemplate(withLeafs(2, 2))),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			from, err := NewFabric("test", tc.from, WithLogger(logging.NewNopLogger()))
+			if err != nil {
+				t.Fatalf("NewFabric(from): %v", err)
+			}
+			to, err := NewFabric("test", tc.to, WithLogger(logging.NewNopLogger()), WithAllocations(from.GetAllocations()))
+			if err != nil {
+				t.Fatalf("NewFabric(to): %v", err)
+			}
+
+			links := map[string]struct{}{}
+			for _, name := range getLinkNames(to) {
+				links[name] = struct{}{}
+			}
+			for _, name := range getLinkNames(from) {
+				if _, ok := links[name]; !ok {
+					t.Errorf("\n%s\nlink %s of the previous render is not rendered, got links %v", tc.reason, name, getLinkNames(to))
+				}
+			}
+
+			// a new link never reuses an interface of another link
+			used := map[string]string{}
+			for _, l := range to.GetFabricLinks() {
+				if l.GetLag() {
+					continue
+				}
+				for _, ep := range []*Endpoint{l.GetEndpointA(), l.GetEndpointB()} {
+					k := ep.Node.GetNodeName() + ":" + ep.IfName
+					if other, ok := used[k]; ok {
+						t.Errorf("\n%s\ninterface %s is used by link %s and %s", tc.reason, k, other, l.GetName())
+					}
+					used[k] = l.GetName()
+				}
+			}
+		})
+	}
+}
+
+func TestAllocationReorderedPodTemplates(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		from   *topov1alpha1.FabricTemplate
+		to     *topov1alpha1.FabricTemplate
+	}{
+		"NamedPodTemplates": {
+			reason: "Named pod templates should keep their pods when they are reordered.",
+			from: fabricTemplate(2,
+				podTemplate(withPodName("a"), withLeafs(2, 1)),
+				podTemplate(withPodName("b"), withPodNumber(2), withLeafs(3, 1))),
+			to: fabricTemplate(2,
+				podTemplate(withPodName("b"), withPodNumber(2), withLeafs(3, 1)),
+				podTemplate(withPodName("a"), withLeafs(2, 1))),
+		},
+		"RemovedPodTemplate": {
+			reason: "Named pod templates should keep their pods when a pod template before them is removed.",
+			from: fabricTemplate(2,
+				podTemplate(withPodName("a")),
+				podTemplate(withPodName("b"), withLeafs(3, 1))),
+			to: fabricTemplate(2,
+				podTemplate(withPodName("b"), withLeafs(3, 1))),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			from, err := NewFabric("test", tc.from, WithLogger(logging.NewNopLogger()))
+			if err != nil {
+				t.Fatalf("NewFabric(from): %v", err)
+			}
+			to, err := NewFabric("test", tc.to, WithLogger(logging.NewNopLogger()), WithAllocations(from.GetAllocations()))
+			if err != nil {
+				t.Fatalf("NewFabric(to): %v", err)
+			}
+
+			want := map[string]uint32{}
+			for _, pa := range from.GetAllocations().Pods {
+				want[pa.PodTemplate] = pa.PodIndex
+			}
+			for _, pa := range to.GetAllocations().Pods {
+				if idx, ok := want[pa.PodTemplate]; ok && idx != pa.PodIndex {
+					t.Errorf("\n%s\npod template %s: want pod index %d, got %d", tc.reason, pa.PodTemplate, idx, pa.PodIndex)
+				}
+			}
+
+			// the pods are not renamed, so every node was rendered before
+			nodes := map[string]struct{}{}
+			for _, name := range getNodeNames(from) {
+				nodes[name] = struct{}{}
+			}
+			for _, name := range getNodeNames(to) {
+				if _, ok := nodes[name]; !ok {
+					t.Errorf("\n%s\nnode %s was not rendered before, got nodes %v", tc.reason, name, getNodeNames(from))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	"fmt"
+	"sort"
+
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+)
+
+// DiffNode holds the name and labels of a node compared by the diff
+// +k8s:deepcopy-gen=false
+type DiffNode struct {
+	Name   string
+	Labels map[string]string
+}
+
+// DiffLink holds the name, labels and endpoints of a link compared by the diff
+// +k8s:deepcopy-gen=false
+type DiffLink struct {
+	Name       string
+	Labels     map[string]string
+	NodeA      string
+	InterfaceA string
+	NodeB      string
+	InterfaceB string
+	Lag        bool
+}
+
+// nodePair identifies the links between 2 nodes
+type nodePair struct {
+	nodeA string
+	nodeB string
+	lag   bool
+}
+
+func (l *DiffLink) getNodePair() nodePair {
+	return nodePair{nodeA: l.NodeA, nodeB: l.NodeB, lag: l.Lag}
+}
+
+// Diff returns the differences between the nodes and links of 2 fabrics, from is
+// the fabric of the old template revision and to the fabric of the new one
+func Diff(from, to Fabric) *topov1alpha1.FabricDiff {
+	oldNodes, oldLinks := getDiffResources(from)
+	newNodes, newLinks := getDiffResources(to)
+	return DiffResources(oldNodes, oldLinks, newNodes, newLinks)
+}
+
+func getDiffResources(f Fabric) ([]*DiffNode, []*DiffLink) {
+	nodes := make([]*DiffNode, 0)
+	for _, n := range f.GetFabricNodes() {
+		nodes = append(nodes, &DiffNode{Name: n.GetNodeName(), Labels: n.GetLabels()})
+	}
+	links := make([]*DiffLink, 0)
+	for _, l := range f.GetFabricLinks() {
+		links = append(links, &DiffLink{
+			Name:       l.GetName(),
+			NodeA:      l.GetEndpointA().Node.GetNodeName(),
+			InterfaceA: l.GetEndpointA().IfName,
+			NodeB:      l.GetEndpointB().Node.GetNodeName(),
+			InterfaceB: l.GetEndpointB().IfName,
+			Lag:        l.GetLag(),
+		})
+	}
+	return nodes, links
+}
+
+// DiffResources returns the differences between the old and new nodes and links.
+// Nodes are matched by name. Links are matched by name, the name of a link changes
+// with its interfaces, so the remaining links between the same nodes are reported
+// as moved with their reindexed interfaces, the others as added or removed.
+func DiffResources(oldNodes []*DiffNode, oldLinks []*DiffLink, newNodes []*DiffNode, newLinks []*DiffLink) *topov1alpha1.FabricDiff {
+	d := &topov1alpha1.FabricDiff{
+		Nodes: diffNodes(oldNodes, newNodes),
+		Links: &topov1alpha1.FabricDiffLinks{},
+	}
+
+	// links with the same name have the same endpoints
+	names := map[string]struct{}{}
+	for _, l := range newLinks {
+		names[l.Name] = struct{}{}
+	}
+	oldPairs := map[nodePair][]*DiffLink{}
+	oldNames := map[string]*DiffLink{}
+	for _, l := range oldLinks {
+		oldNames[l.Name] = l
+		if _, ok := names[l.Name]; !ok {
+			oldPairs[l.getNodePair()] = append(oldPairs[l.getNodePair()], l)
+		}
+	}
+	newPairs := map[nodePair][]*DiffLink{}
+	for _, l := range newLinks {
+		o, ok := oldNames[l.Name]
+		if !ok {
+			newPairs[l.getNodePair()] = append(newPairs[l.getNodePair()], l)
+			continue
+		}
+		if changes := diffLabels(o.Labels, l.Labels); len(changes) > 0 {
+			d.Links.Relabeled = append(d.Links.Relabeled, &topov1alpha1.FabricDiffLabels{Name: l.Name, Changes: changes})
+		}
+	}
+
+	for pair, newPairLinks := range newPairs {
+		removed, moved, added := matchLinks(oldPairs[pair], newPairLinks)
+		delete(oldPairs, pair)
+		for _, l := range removed {
+			d.Links.Removed = append(d.Links.Removed, l.Name)
+		}
+		for _, l := range added {
+			d.Links.Added = append(d.Links.Added, l.Name)
+		}
+		for _, m := range moved {
+			d.Links.Moved = append(d.Links.Moved, &topov1alpha1.FabricDiffLink{From: m[0].Name, To: m[1].Name})
+			if m[0].InterfaceA != m[1].InterfaceA {
+				d.ReindexedInterfaces = append(d.ReindexedInterfaces, &topov1alpha1.FabricDiffInterface{
+					Node: m[1].NodeA, From: m[0].InterfaceA, To: m[1].InterfaceA,
+				})
+			}
+			if m[0].InterfaceB != m[1].InterfaceB {
+				d.ReindexedInterfaces = append(d.ReindexedInterfaces, &topov1alpha1.FabricDiffInterface{
+					Node: m[1].NodeB, From: m[0].InterfaceB, To: m[1].InterfaceB,
+				})
+			}
+		}
+	}
+	for _, oldPairLinks := range oldPairs {
+		for _, l := range oldPairLinks {
+			d.Links.Removed = append(d.Links.Removed, l.Name)
+		}
+	}
+
+	sort.Strings(d.Links.Added)
+	sort.Strings(d.Links.Removed)
+	sort.Slice(d.Links.Moved, func(i, j int) bool {
+		return d.Links.Moved[i].From < d.Links.Moved[j].From
+	})
+	sort.Slice(d.Links.Relabeled, func(i, j int) bool {
+		return d.Links.Relabeled[i].Name < d.Links.Relabeled[j].Name
+	})
+	sort.Slice(d.ReindexedInterfaces, func(i, j int) bool {
+		if d.ReindexedInterfaces[i].Node != d.ReindexedInterfaces[j].Node {
+			return d.ReindexedInterfaces[i].Node < d.ReindexedInterfaces[j].Node
+		}
+		return d.ReindexedInterfaces[i].From < d.ReindexedInterfaces[j].From
+	})
+	return d
+}
+
+// matchLinks matches the old and new links between the same nodes. A link keeping
+// the interface on one of the nodes is matched first, the other links are matched
+// in the order of their names. Links without match are removed or added.
+func matchLinks(oldLinks, newLinks []*DiffLink) ([]*DiffLink, [][2]*DiffLink, []*DiffLink) {
+	sort.Slice(oldLinks, func(i, j int) bool { return oldLinks[i].Name < oldLinks[j].Name })
+	sort.Slice(newLinks, func(i, j int) bool { return newLinks[i].Name < newLinks[j].Name })
+
+	moved := make([][2]*DiffLink, 0)
+	matched := map[*DiffLink]struct{}{}
+	match := func(isMatch func(o, n *DiffLink) bool) {
+		for _, o := range oldLinks {
+			if _, ok := matched[o]; ok {
+				continue
+			}
+			for _, n := range newLinks {
+				if _, ok := matched[n]; ok || !isMatch(o, n) {
+					continue
+				}
+				matched[o] = struct{}{}
+				matched[n] = struct{}{}
+				moved = append(moved, [2]*DiffLink{o, n})
+				break
+			}
+		}
+	}
+	match(func(o, n *DiffLink) bool { return o.InterfaceA == n.InterfaceA || o.InterfaceB == n.InterfaceB })
+	match(func(o, n *DiffLink) bool { return true })
+
+	removed := make([]*DiffLink, 0)
+	for _, o := range oldLinks {
+		if _, ok := matched[o]; !ok {
+			removed = append(removed, o)
+		}
+	}
+	added := make([]*DiffLink, 0)
+	for _, n := range newLinks {
+		if _, ok := matched[n]; !ok {
+			added = append(added, n)
+		}
+	}
+	return removed, moved, added
+}
+
+// diffNodes matches the nodes by name and reports the changed labels per node
+func diffNodes(oldNodes, newNodes []*DiffNode) *topov1alpha1.FabricDiffNodes {
+	d := &topov1alpha1.FabricDiffNodes{}
+	old := map[string]*DiffNode{}
+	for _, n := range oldNodes {
+		old[n.Name] = n
+	}
+	for _, n := range newNodes {
+		o, ok := old[n.Name]
+		if !ok {
+			d.Added = append(d.Added, n.Name)
+			continue
+		}
+		delete(old, n.Name)
+		if changes := diffLabels(o.Labels, n.Labels); len(changes) > 0 {
+			d.Relabeled = append(d.Relabeled, &topov1alpha1.FabricDiffLabels{Name: n.Name, Changes: changes})
+		}
+	}
+	for name := range old {
+		d.Removed = append(d.Removed, name)
+	}
+
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Slice(d.Relabeled, func(i, j int) bool {
+		return d.Relabeled[i].Name < d.Relabeled[j].Name
+	})
+	return d
+}
+
+// diffLabels returns the changed labels as key: old -> new, sorted by key. Only the
+// new labels are compared, labels added by others are kept when the resources are
+// applied and the labels which are no longer rendered are not removed.
+func diffLabels(oldLabels, newLabels map[string]string) []string {
+	changes := make([]string, 0)
+	for k := range newLabels {
+		if oldLabels[k] != newLabels[k] {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", k, oldLabels[k], newLabels[k]))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
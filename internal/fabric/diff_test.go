@@ -0,0 +1,208 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+)
+
+func diffLink(name, nodeA, ifA, nodeB, ifB string) *DiffLink {
+	return &DiffLink{Name: name, NodeA: nodeA, InterfaceA: ifA, NodeB: nodeB, InterfaceB: ifB}
+}
+
+func TestDiffResources(t *testing.T) {
+	type args struct {
+		oldNodes []*DiffNode
+		oldLinks []*DiffLink
+		newNodes []*DiffNode
+		newLinks []*DiffLink
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *topov1alpha1.FabricDiff
+	}{
+		"NoChanges": {
+			reason: "Nodes and links with the same name should not be reported.",
+			args: args{
+				oldNodes: []*DiffNode{{Name: "pod1-leaf1"}},
+				oldLinks: []*DiffLink{diffLink("l1", "pod1-spine1", "int-1/1", "pod1-leaf1", "int-1/49")},
+				newNodes: []*DiffNode{{Name: "pod1-leaf1"}},
+				newLinks: []*DiffLink{diffLink("l1", "pod1-spine1", "int-1/1", "pod1-leaf1", "int-1/49")},
+			},
+			want: &topov1alpha1.FabricDiff{
+				Nodes: &topov1alpha1.FabricDiffNodes{},
+				Links: &topov1alpha1.FabricDiffLinks{},
+			},
+		},
+		"AddedAndRemoved": {
+			reason: "Nodes and links which do not match should be reported as added or removed.",
+			args: args{
+				oldNodes: []*DiffNode{{Name: "pod1-leaf1"}},
+				oldLinks: []*DiffLink{diffLink("l1", "pod1-spine1", "int-1/1", "pod1-leaf1", "int-1/49")},
+				newNodes: []*DiffNode{{Name: "pod1-leaf2"}},
+				newLinks: []*DiffLink{diffLink("l2", "pod1-spine1", "int-1/2", "pod1-leaf2", "int-1/49")},
+			},
+			want: &topov1alpha1.FabricDiff{
+				Nodes: &topov1alpha1.FabricDiffNodes{Added: []string{"pod1-leaf2"}, Removed: []string{"pod1-leaf1"}},
+				Links: &topov1alpha1.FabricDiffLinks{Added: []string{"l2"}, Removed: []string{"l1"}},
+			},
+		},
+		"MovedOnOneNode": {
+			reason: "A link between the same nodes on another interface should be reported as moved with the reindexed interface.",
+			args: args{
+				oldLinks: []*DiffLink{diffLink("l1", "pod1-spine1", "int-1/1", "pod1-leaf1", "int-1/49")},
+				newLinks: []*DiffLink{diffLink("l1-new", "pod1-spine1", "int-1/3", "pod1-leaf1", "int-1/49")},
+			},
+			want: &topov1alpha1.FabricDiff{
+				Nodes: &topov1alpha1.FabricDiffNodes{},
+				Links: &topov1alpha1.FabricDiffLinks{
+					Moved: []*topov1alpha1.FabricDiffLink{{From: "l1", To: "l1-new"}},
+				},
+				ReindexedInterfaces: []*topov1alpha1.FabricDiffInterface{
+					{Node: "pod1-spine1", From: "int-1/1", To: "int-1/3"},
+				},
+			},
+		},
+		"ReindexedOnBothNodes": {
+			reason: "A moved link should report the reindexed interfaces of both nodes.",
+			args: args{
+				oldLinks: []*DiffLink{diffLink("l1", "pod1-spine1", "int-1/1", "pod1-leaf1", "int-1/49")},
+				newLinks: []*DiffLink{diffLink("l1-new", "pod1-spine1", "int-1/2", "pod1-leaf1", "int-1/50")},
+			},
+			want: &topov1alpha1.FabricDiff{
+				Nodes: &topov1alpha1.FabricDiffNodes{},
+				Links: &topov1alpha1.FabricDiffLinks{
+					Moved: []*topov1alpha1.FabricDiffLink{{From: "l1", To: "l1-new"}},
+				},
+				ReindexedInterfaces: []*topov1alpha1.FabricDiffInterface{
+					{Node: "pod1-leaf1", From: "int-1/49", To: "int-1/50"},
+					{Node: "pod1-spine1", From: "int-1/1", To: "int-1/2"},
+				},
+			},
+		},
+		"MatchKeepingInterface": {
+			reason: "A link keeping the interface on one of the nodes should be matched before the links in name order.",
+			args: args{
+				oldLinks: []*DiffLink{
+					diffLink("a", "pod1-spine1", "int-1/1", "pod1-leaf1", "int-1/49"),
+					diffLink("b", "pod1-spine1", "int-1/2", "pod1-leaf1", "int-1/50"),
+				},
+				newLinks: []*DiffLink{
+					diffLink("c", "pod1-spine1", "int-1/3", "pod1-leaf1", "int-1/50"),
+					diffLink("d", "pod1-spine1", "int-1/4", "pod1-leaf1", "int-1/51"),
+				},
+			},
+			want: &topov1alpha1.FabricDiff{
+				Nodes: &topov1alpha1.FabricDiffNodes{},
+				Links: &topov1alpha1.FabricDiffLinks{
+					Moved: []*topov1alpha1.FabricDiffLink{{From: "a", To: "d"}, {From: "b", To: "c"}},
+				},
+				ReindexedInterfaces: []*topov1alpha1.FabricDiffInterface{
+					{Node: "pod1-leaf1", From: "int-1/49", To: "int-1/51"},
+					{Node: "pod1-spine1", From: "int-1/1", To: "int-1/4"},
+					{Node: "pod1-spine1", From: "int-1/2", To: "int-1/3"},
+				},
+			},
+		},
+		"MoreLinksBetweenNodes": {
+			reason: "The links between the same nodes which cannot be matched should be reported as added.",
+			args: args{
+				oldLinks: []*DiffLink{diffLink("a", "pod1-spine1", "int-1/1", "pod1-leaf1", "int-1/49")},
+				newLinks: []*DiffLink{
+					diffLink("b", "pod1-spine1", "int-1/1", "pod1-leaf1", "int-1/50"),
+					diffLink("c", "pod1-spine1", "int-1/2", "pod1-leaf1", "int-1/51"),
+				},
+			},
+			want: &topov1alpha1.FabricDiff{
+				Nodes: &topov1alpha1.FabricDiffNodes{},
+				Links: &topov1alpha1.FabricDiffLinks{
+					Added: []string{"c"},
+					Moved: []*topov1alpha1.FabricDiffLink{{From: "a", To: "b"}},
+				},
+				ReindexedInterfaces: []*topov1alpha1.FabricDiffInterface{
+					{Node: "pod1-leaf1", From: "int-1/49", To: "int-1/50"},
+				},
+			},
+		},
+		"Relabeled": {
+			reason: "Only the rendered labels should be compared, labels added by others are ignored.",
+			args: args{
+				oldNodes: []*DiffNode{{Name: "pod1-leaf1", Labels: map[string]string{"position": "leaf", "external": "x"}}},
+				oldLinks: []*DiffLink{{Name: "l1", Labels: map[string]string{"topology": "a", "external": "x"}}},
+				newNodes: []*DiffNode{{Name: "pod1-leaf1", Labels: map[string]string{"position": "spine"}}},
+				newLinks: []*DiffLink{{Name: "l1", Labels: map[string]string{"topology": "b"}}},
+			},
+			want: &topov1alpha1.FabricDiff{
+				Nodes: &topov1alpha1.FabricDiffNodes{
+					Relabeled: []*topov1alpha1.FabricDiffLabels{{Name: "pod1-leaf1", Changes: []string{"position: leaf -> spine"}}},
+				},
+				Links: &topov1alpha1.FabricDiffLinks{
+					Relabeled: []*topov1alpha1.FabricDiffLabels{{Name: "l1", Changes: []string{"topology: a -> b"}}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DiffResources(tc.args.oldNodes, tc.args.oldLinks, tc.args.newNodes, tc.args.newLinks)
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("\n%s\nDiffResources(...): want %s, got %s", tc.reason, toJSON(tc.want), toJSON(got))
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from, err := NewFabric("test", fabricTemplate(2, podTemplate(withLeafs(2, 1))), WithLogger(logging.NewNopLogger()))
+	if err != nil {
+		t.Fatalf("NewFabric(from): %v", err)
+	}
+	// without allocations the spine interfaces of the second leaf and the leaf
+	// interfaces of the second spine are reindexed when the max uplinks change
+	to, err := NewFabric("test", fabricTemplate(3, podTemplate(withLeafs(2, 1))), WithLogger(logging.NewNopLogger()))
+	if err != nil {
+		t.Fatalf("NewFabric(to): %v", err)
+	}
+
+	d := Diff(from, to)
+	if len(d.Nodes.Added) != 0 || len(d.Nodes.Removed) != 0 || len(d.Nodes.Relabeled) != 0 {
+		t.Errorf("Diff(...): want no node changes, got %s", toJSON(d.Nodes))
+	}
+	if len(d.Links.Added) != 0 || len(d.Links.Removed) != 0 {
+		t.Errorf("Diff(...): want no added or removed links, got %s", toJSON(d.Links))
+	}
+	// only the link between spine1 and leaf1 keeps its interfaces, the 2 spines
+	// move their link to leaf2 and the 2 leafs move their link to spine2
+	if len(d.Links.Moved) != 3 || len(d.ReindexedInterfaces) != 4 {
+		t.Errorf("Diff(...): want 3 moved links with 4 reindexed interfaces, got %s", toJSON(d))
+	}
+}
+
+func toJSON(o interface{}) string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
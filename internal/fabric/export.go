@@ -0,0 +1,175 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/cabling"
+	"github.com/yndd/topology/internal/clab"
+)
+
+// dotRanks orders the positions from top to bottom in the DOT graph
+var dotRanks = []topov1alpha1.Position{
+	topov1alpha1.PositionSuperspine,
+	topov1alpha1.PositionBorderLeaf,
+	topov1alpha1.PositionSpine,
+	topov1alpha1.PositionLeaf,
+}
+
+// Graph is the node-link JSON graph of a fabric
+type Graph struct {
+	Name  string       `json:"name"`
+	Nodes []*GraphNode `json:"nodes"`
+	Links []*GraphLink `json:"links"`
+}
+
+type GraphNode struct {
+	ID         string                `json:"id"`
+	Position   topov1alpha1.Position `json:"position"`
+	PodIndex   uint32                `json:"podIndex,omitempty"`
+	NodeIndex  uint32                `json:"nodeIndex"`
+	VendorType targetv1.VendorType   `json:"vendorType,omitempty"`
+	Platform   string                `json:"platform,omitempty"`
+}
+
+type GraphLink struct {
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	SourceInterface string `json:"sourceInterface"`
+	Target          string `json:"target"`
+	TargetInterface string `json:"targetInterface"`
+	Lag             bool   `json:"lag,omitempty"`
+	LagMember       bool   `json:"lagMember,omitempty"`
+}
+
+// ExportDOT returns the physical links of the fabric as undirected Graphviz graph,
+// the nodes are ranked per position with the superspines on top
+func (f *fabric) ExportDOT() ([]byte, error) {
+	nodes := f.GetFabricNodes()
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "graph %q {\n", f.name)
+	fmt.Fprintf(b, "\tnode [shape=box];\n")
+	for _, n := range nodes {
+		fmt.Fprintf(b, "\t%q [label=%q];\n", n.GetNodeName(), fmt.Sprintf("%s\n%s", n.GetNodeName(), n.GetPlatform()))
+	}
+	for _, pos := range dotRanks {
+		rank := &bytes.Buffer{}
+		for _, n := range nodes {
+			if n.GetPosition() == pos {
+				fmt.Fprintf(rank, " %q;", n.GetNodeName())
+			}
+		}
+		if rank.Len() > 0 {
+			fmt.Fprintf(b, "\t{ rank=same;%s }\n", rank.String())
+		}
+	}
+	for _, l := range f.GetFabricLinks() {
+		if l.GetLag() {
+			continue
+		}
+		fmt.Fprintf(b, "\t%q -- %q [taillabel=%q, headlabel=%q];\n",
+			l.GetEndpointA().Node.GetNodeName(), l.GetEndpointB().Node.GetNodeName(),
+			l.GetEndpointA().IfName, l.GetEndpointB().IfName)
+	}
+	fmt.Fprintf(b, "}\n")
+	return b.Bytes(), nil
+}
+
+// ExportJSON returns the nodes and links of the fabric, including the logical lag
+// links, as node-link graph
+func (f *fabric) ExportJSON() ([]byte, error) {
+	g := &Graph{
+		Name:  f.name,
+		Nodes: make([]*GraphNode, 0),
+		Links: make([]*GraphLink, 0),
+	}
+	for _, n := range f.GetFabricNodes() {
+		g.Nodes = append(g.Nodes, &GraphNode{
+			ID:         n.GetNodeName(),
+			Position:   n.GetPosition(),
+			PodIndex:   n.GetPodIndex(),
+			NodeIndex:  n.GetNodeIndex(),
+			VendorType: n.GetVendorType(),
+			Platform:   n.GetPlatform(),
+		})
+	}
+	for _, l := range f.GetFabricLinks() {
+		g.Links = append(g.Links, &GraphLink{
+			ID:              l.GetName(),
+			Source:          l.GetEndpointA().Node.GetNodeName(),
+			SourceInterface: l.GetEndpointA().IfName,
+			Target:          l.GetEndpointB().Node.GetNodeName(),
+			TargetInterface: l.GetEndpointB().IfName,
+			Lag:             l.GetLag(),
+			LagMember:       l.GetLagMember(),
+		})
+	}
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// ExportContainerlab returns the nodes and physical links of the fabric as
+// containerlab topology
+func (f *fabric) ExportContainerlab() ([]byte, error) {
+	t := clab.NewTopology(f.name)
+	for _, n := range f.GetFabricNodes() {
+		t.AddNode(n.GetNodeName(), n.GetVendorType(), n.GetPlatform(), n.GetPosition())
+	}
+	for _, l := range f.GetFabricLinks() {
+		if l.GetLag() {
+			continue
+		}
+		t.AddLink(l.GetEndpointA().Node.GetNodeName(), l.GetEndpointA().IfName,
+			l.GetEndpointB().Node.GetNodeName(), l.GetEndpointB().IfName)
+	}
+	return t.Marshal()
+}
+
+// ExportCablingPlan adds the nodes and physical links of the fabric to the cabling
+// plan, a cable is assigned to the pod of its leaf or spine and to the plane of its
+// superspine
+func (f *fabric) ExportCablingPlan(p *cabling.Plan) {
+	for _, n := range f.GetFabricNodes() {
+		p.AddNode(n.GetNodeName(), n.GetVendorType(), n.GetPlatform())
+	}
+	for _, l := range f.GetFabricLinks() {
+		if l.GetLag() {
+			continue
+		}
+		c := &cabling.Cable{
+			NodeA: l.GetEndpointA().Node.GetNodeName(),
+			PortA: l.GetEndpointA().IfName,
+			NodeB: l.GetEndpointB().Node.GetNodeName(),
+			PortB: l.GetEndpointB().IfName,
+		}
+		for _, n := range []FabricNode{l.GetEndpointA().Node, l.GetEndpointB().Node} {
+			switch n.GetPosition() {
+			case topov1alpha1.PositionLeaf, topov1alpha1.PositionSpine:
+				c.Pod = n.GetPodIndex()
+			case topov1alpha1.PositionSuperspine:
+				// the node index of a superspine is its plane index
+				c.Plane = n.GetNodeIndex()
+			}
+		}
+		p.AddCable(c)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/cabling"
+)
+
+func withLag() podTemplateModifier {
+	return func(p *topov1alpha1.PodTemplate) { p.Tier3.Lag = true }
+}
+
+func TestExportCablingPlan(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		template   *topov1alpha1.FabricTemplate
+		wantCables int
+		want       []*cabling.BOMEntry
+	}{
+		"SingleUplink": {
+			reason:     "Every leaf should be cabled to every spine.",
+			template:   fabricTemplate(2, podTemplate(withLeafs(3, 1))),
+			wantCables: 6,
+			want: []*cabling.BOMEntry{
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D2", Devices: 3, Ports: 6},
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D3", Devices: 2, Ports: 6},
+			},
+		},
+		"LagUplinks": {
+			reason:     "The members of a lag should be cabled, the logical lag link is not a cable.",
+			template:   fabricTemplate(2, podTemplate(withLeafs(2, 2), withLag())),
+			wantCables: 8,
+			want: []*cabling.BOMEntry{
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D2", Devices: 2, Ports: 8},
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D3", Devices: 2, Ports: 8},
+			},
+		},
+		"MultiplePods": {
+			reason:     "The devices and ports of every pod should be counted.",
+			template:   fabricTemplate(2, podTemplate(withPodNumber(2), withLeafs(2, 1))),
+			wantCables: 8,
+			want: []*cabling.BOMEntry{
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D2", Devices: 4, Ports: 8},
+				{VendorType: targetv1.VendorTypeNokiaSRL, Platform: "IXR-D3", Devices: 4, Ports: 8},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewFabric("test", tc.template, WithLogger(logging.NewNopLogger()))
+			if err != nil {
+				t.Fatalf("NewFabric(...): %v", err)
+			}
+			p := cabling.NewPlan()
+			f.ExportCablingPlan(p)
+			if len(p.Cables) != tc.wantCables {
+				t.Errorf("\n%s\nExportCablingPlan(...): want %d cables, got %d", tc.reason, tc.wantCables, len(p.Cables))
+			}
+			if got := p.GetBOM(); !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("\n%s\nGetBOM(): want %s, got %s", tc.reason, toJSON(tc.want), toJSON(got))
+			}
+		})
+	}
+}
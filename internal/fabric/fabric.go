@@ -20,11 +20,13 @@ package fabric
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	"github.com/yndd/ndd-runtime/pkg/meta"
 	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"github.com/yndd/topology/internal/cabling"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -46,29 +48,65 @@ func WithClient(c client.Client) Option {
 	}
 }
 
+// WithAllocations specifies the indexes allocated by a previous render of the fabric,
+// they are reused such that the existing cabling stays stable when the fabric changes.
+func WithAllocations(a *topov1alpha1.FabricAllocations) Option {
+	return func(f Fabric) {
+		f.SetAllocations(a)
+	}
+}
+
+// WithInterfaceProfiles specifies the interface profiles the fabric uses to name interfaces.
+func WithInterfaceProfiles(p InterfaceProfiles) Option {
+	return func(f Fabric) {
+		f.SetInterfaceProfiles(p)
+	}
+}
+
 // +k8s:deepcopy-gen=false
 type Fabric interface {
 	GetFabricNodes() []FabricNode
 	GetFabricLinks() []FabricLink
 	PrintNodes()
 	PrintLinks()
+	GetAllocations() *topov1alpha1.FabricAllocations
+	// ExportDOT returns the fabric as Graphviz DOT graph
+	ExportDOT() ([]byte, error)
+	// ExportJSON returns the fabric as node-link JSON graph
+	ExportJSON() ([]byte, error)
+	// ExportContainerlab returns the fabric as containerlab topology file
+	ExportContainerlab() ([]byte, error)
+	// ExportCablingPlan adds the cables and devices of the fabric to the cabling plan
+	ExportCablingPlan(p *cabling.Plan)
 
 	SetLogger(logger logging.Logger)
 	SetClient(c client.Client)
+	SetInterfaceProfiles(p InterfaceProfiles)
+	SetAllocations(a *topov1alpha1.FabricAllocations)
 }
 
 func NewFabric(namespaceName string, template *topov1alpha1.FabricTemplate, opts ...Option) (Fabric, error) {
 	f := &fabric{
+		name:            namespaceName,
 		tier1Nodes:      make([]FabricNode, 0),
+		borderLeafNodes: make([]FabricNode, 0),
 		pods:            map[uint32]*podInfo{},
 		tier2tier3Links: make([]FabricLink, 0),
 		tier1tier2Links: make([]FabricLink, 0),
+		borderLeafLinks: make([]FabricLink, 0),
 	}
 
 	for _, opt := range opts {
 		opt(f)
 	}
 
+	if f.profiles == nil {
+		f.profiles = NewInterfaceProfiles()
+	}
+	if f.alloc == nil {
+		f.alloc = newAllocator(nil)
+	}
+
 	// a template can have multiple template/definition references so we need to parse them
 	// to build one fabric topology
 	mergedTemplate, err := f.parseTemplate(template)
@@ -78,13 +116,20 @@ func NewFabric(namespaceName string, template *topov1alpha1.FabricTemplate, opts
 
 	f.log.Debug("mergedTemplate", "mergedTemplate", mergedTemplate)
 
+	// the pod indexes are allocated per pod template so they do not depend
+	// on the order of the pod templates, the pod indexes of a previous render are reused
+	podIndexes, err := f.alloc.allocatePodIndexes(mergedTemplate, f.podKeys)
+	if err != nil {
+		return nil, err
+	}
+
 	// process leaf/spine nodes
 	// p is number of pod definitions
 	for p, pod := range mergedTemplate.Pod {
 		// i is the number of pods in a definition
 		for i := uint32(0); i < pod.GetPodNumber(); i++ {
-			// podIndex is pod template index * pod index within the template
-			podIndex := (uint32(p) + 1) * (i + 1)
+			// podIndex is the first pod index of the pod template + pod index within the template
+			podIndex := podIndexes[p] + i
 
 			//log.Debug("podIndex", "podIndex", podIndex)
 
@@ -96,12 +141,17 @@ func NewFabric(namespaceName string, template *topov1alpha1.FabricTemplate, opts
 	}
 
 	// proces superspines
-	// the superspine is equal to the amount of spines per pod and multiplied with the number in the template
+	// by default the planes are equal to the amount of spines per pod and every plane has
+	// the number of superspines in the template, the plane template can define them explicitly
+	var planes *planeModel
 	if mergedTemplate.Tier1 != nil {
-		superspines := f.getSuperSPines()
+		planes, err = newPlaneModel(mergedTemplate, f.getSuperSPines())
+		if err != nil {
+			return nil, err
+		}
 		// process superspine nodes
-		for n := uint32(0); n < superspines; n++ {
-			for m := uint32(0); m < mergedTemplate.Tier1.NodeNumber; m++ {
+		for n := uint32(0); n < planes.planes; n++ {
+			for m := uint32(0); m < planes.superspinesPerPlane; m++ {
 				// venndor Index is used to map to the particular node based on modulo
 				// if 1 vendor -> all nodes are from 1 vendor
 				// if 2 vendors -> all odd nodes will be vendor A and all even nodes will be vendor B
@@ -110,15 +160,35 @@ func NewFabric(namespaceName string, template *topov1alpha1.FabricTemplate, opts
 
 				// PlaneIndex: m + 1 -> starts counting from 1, used when multiple nodes are used in the superspine plane
 				// NodeIndex: n + 1 -> could also be called the Plane Index
-				tier1Node := NewSuperspineFabricNode(m+1, n+1, mergedTemplate.Tier1.VendorInfo[vendorIdx], f.log)
+				vendorInfo := mergedTemplate.Tier1.VendorInfo[vendorIdx]
+				tier1Node := NewSuperspineFabricNode(m+1, n+1, vendorInfo, f.getInterfaceProfile(vendorInfo), f.log)
 
 				f.addNode(topov1alpha1.PositionSuperspine, tier1Node, 0)
 			}
 		}
 	}
 
+	// process borderleaf nodes
+	if mergedTemplate.HasBorderLeaf() {
+		vendorNum := len(mergedTemplate.BorderLeaf.VendorInfo)
+		for n := uint32(0); n < mergedTemplate.BorderLeaf.NodeNumber; n++ {
+			vendorIdx := n % uint32(vendorNum)
+			// NodeIndex: n + 1 -> the nodeIndex within the borderleaf tier, counting starts from 1
+			vendorInfo := mergedTemplate.BorderLeaf.VendorInfo[vendorIdx]
+			borderLeafNode := NewBorderLeafFabricNode(n+1, mergedTemplate.BorderLeaf.UplinksPerNode, mergedTemplate.BorderLeaf.Lag, vendorInfo, f.getInterfaceProfile(vendorInfo), f.log)
+
+			f.addNode(topov1alpha1.PositionBorderLeaf, borderLeafNode, 0)
+		}
+	}
+
+	// every node needs an interface profile before the interfaces of the links are named
+	if err := f.validateInterfaceProfiles(); err != nil {
+		return nil, err
+	}
+
 	// process spine-leaf links
-	for _, podInfo := range f.pods {
+	for _, podIndex := range f.getPodIndexes() {
+		podInfo := f.pods[podIndex]
 		for n, tier2Node := range podInfo.tier2Nodes {
 			tier2NodeIndex := uint32(n) + 1
 			for m, tier3Node := range podInfo.tier3Nodes {
@@ -142,25 +212,31 @@ func NewFabric(namespaceName string, template *topov1alpha1.FabricTemplate, opts
 				// actual leafs   = tier3NodeIndex - 1 -> counting from 0
 				// actual spines  = tier2NodeIndex - 1 -> counting from 0
 				// max uplinks    = mergedTemplate.MaxUplinksTier3ToTier2
+				members := make([]FabricLink, 0, uplinksPerNode)
 				for u := uint32(0); u < uplinksPerNode; u++ {
-					epA := &Endpoint{
-						Node:   tier2Node,
-						IfName: tier2Node.GetInterfaceName(u + 1 + ((tier3NodeIndex - 1) * mergedTemplate.MaxUplinksTier3ToTier2)),
-					}
-					epB := &Endpoint{
-						Node:   tier3Node,
-						IfName: tier3Node.GetInterfaceNameWithPlatfromOffset(u + 1 + ((tier2NodeIndex - 1) * mergedTemplate.MaxUplinksTier3ToTier2)),
-					}
-					f.addLink(topov1alpha1.PositionSpine, NewFabricLink(epA, epB))
+					idxA := u + 1 + ((tier3NodeIndex - 1) * mergedTemplate.MaxUplinksTier3ToTier2)
+					idxB := tier3Node.GetUplinkIndex(u + 1 + ((tier2NodeIndex - 1) * mergedTemplate.MaxUplinksTier3ToTier2))
+					members = append(members, f.alloc.allocateLink(tier2Node, idxA, tier3Node, idxB, u+1))
 				}
+				f.addUplinks(topov1alpha1.PositionSpine, tier3Node, members)
 			}
 		}
 	}
 
 	// process superspine-spine links
+	// a spine connects to the superspines of the planes it is assigned to
+	// planeSlots is the max number of spines of a pod connecting to the same plane
+	var planeSlots uint32
+	if planes != nil {
+		planeSlots = f.getPlaneSlots(planes)
+	}
 	for _, tier1Node := range f.tier1Nodes {
-		for p, podInfo := range f.pods {
-			for m, tier2Node := range podInfo.tier2Nodes {
+		plane := tier1Node.GetNodeIndex()
+		for _, p := range f.getPodIndexes() {
+			podInfo := f.pods[p]
+			planeSpines := planes.getPlaneSpines(p, uint32(len(podInfo.tier2Nodes)), plane)
+			for k, spineIndex := range planeSpines {
+				tier2Node := podInfo.tier2Nodes[spineIndex-1]
 
 				// validate if the uplinks per node is not greater than max uplinks
 				// otherwise there is a conflict and the algorithm behind will create
@@ -170,45 +246,127 @@ func NewFabric(namespaceName string, template *topov1alpha1.FabricTemplate, opts
 					return nil, fmt.Errorf("uplink per node %d can not be bigger than maxUplinksTier2ToTier1 %d", uplinksPerNode, mergedTemplate.MaxUplinksTier2ToTier1)
 				}
 
-				// spine and superspine line up so we only create a link if there is a match
-				// on the indexes
-				if (m + 1) == int(tier1Node.GetNodeIndex()) {
-					// the algorithm needs to avoid reindixing if changes happen -> introduced maxNumUplinks
-					// the allocation is first allocating the uplink Index
-					// u represnts the actual uplink index
-					// superspine Index -> actualUplinkId + (actual podIndex  * max uplinks)
-					// spine Index      -> actualUplinkId + (actual spines per plane * max uplinks)
-					// actualUplinkId          = u + 1 -> counting starts at 1
-					// actual PodIndex         = p +1
-					// actual spines per plane = tier1Node.GetNodePlaneIndex() - 1
-					// max uplinks             = mergedTemplate.MaxUplinksTier2ToTier1
+				// the algorithm needs to avoid reindixing if changes happen -> introduced maxNumUplinks
+				// the allocation is first allocating the uplink Index
+				// u represnts the actual uplink index
+				// superspine Index -> actualUplinkId + (actual spine slot * max uplinks)
+				// spine Index      -> actualUplinkId + (actual superspine slot * max uplinks)
+				// actualUplinkId          = u + 1 -> counting starts at 1
+				// actual spine slot       = (PodIndex - 1) * planeSlots + spine rank in the plane - 1
+				// actual superspine slot  = (plane rank of the spine - 1) * superspines per plane + tier1Node.GetNodePlaneIndex() - 1
+				// max uplinks             = mergedTemplate.MaxUplinksTier2ToTier1
+				// with the default plane model planeSlots and the ranks are 1
+				spineSlot := (p-1)*planeSlots + uint32(k) + 1
+				superspineSlot := (planes.getPlaneRank(p, spineIndex, plane)-1)*planes.superspinesPerPlane + tier1Node.GetNodePlaneIndex()
+				members := make([]FabricLink, 0, uplinksPerNode)
+				for u := uint32(0); u < uplinksPerNode; u++ {
+					idxA := u + 1 + ((spineSlot - 1) * mergedTemplate.MaxUplinksTier2ToTier1)
+					idxB := tier2Node.GetUplinkIndex(u + 1 + ((superspineSlot - 1) * mergedTemplate.MaxUplinksTier2ToTier1))
+					members = append(members, f.alloc.allocateLink(tier1Node, idxA, tier2Node, idxB, u+1))
+				}
+				f.addUplinks(topov1alpha1.PositionSuperspine, tier2Node, members)
+			}
+		}
+	}
+
+	// process borderleaf links
+	// the borderleafs connect to the spines of the designated pod, if no pod is designated
+	// the borderleafs connect to all superspines
+	if mergedTemplate.HasBorderLeaf() {
+		if mergedTemplate.BorderLeafPod != nil {
+			podInfo, ok := f.pods[*mergedTemplate.BorderLeafPod]
+			if !ok {
+				return nil, fmt.Errorf("borderLeafPod %d is not defined in the fabric", *mergedTemplate.BorderLeafPod)
+			}
+			// the borderleafs are indexed on the spine after the leafs of the pod
+			borderLeafIndexes := make([]uint32, len(f.borderLeafNodes))
+			for m, borderLeafNode := range f.borderLeafNodes {
+				borderLeafIndexes[m] = f.alloc.allocateBorderLeafIndex(borderLeafNode.GetNodeName(),
+					*mergedTemplate.BorderLeafPod, uint32(len(podInfo.tier3Nodes))+uint32(m)+1)
+			}
+			for n, tier2Node := range podInfo.tier2Nodes {
+				tier2NodeIndex := uint32(n) + 1
+				for m, borderLeafNode := range f.borderLeafNodes {
+					borderLeafIndex := borderLeafIndexes[m]
+
+					uplinksPerNode := borderLeafNode.GetUplinkPerNode()
+					if uplinksPerNode > mergedTemplate.MaxUplinksTier3ToTier2 {
+						return nil, fmt.Errorf("uplink per node %d can not be bigger than maxUplinksTier3ToTier2 %d",
+							uplinksPerNode, mergedTemplate.MaxUplinksTier3ToTier2)
+					}
+
+					// same allocation as the spine-leaf links
+					// spine Index      -> actualUplinkId + (actual borderleafs * max uplinks)
+					// borderleaf Index -> actualUplinkId + (actual spines * max uplinks)
+					members := make([]FabricLink, 0, uplinksPerNode)
 					for u := uint32(0); u < uplinksPerNode; u++ {
-						epA := &Endpoint{
-							Node:   tier1Node,
-							IfName: tier1Node.GetInterfaceName(u + 1 + ((p - 1) * mergedTemplate.MaxUplinksTier2ToTier1)),
-						}
-						epB := &Endpoint{
-							Node:   tier2Node,
-							IfName: tier2Node.GetInterfaceNameWithPlatfromOffset(u + 1 + ((tier1Node.GetNodePlaneIndex() - 1) * mergedTemplate.MaxUplinksTier2ToTier1)),
-						}
-						f.addLink(topov1alpha1.PositionSuperspine, NewFabricLink(epA, epB))
+						idxA := u + 1 + ((borderLeafIndex - 1) * mergedTemplate.MaxUplinksTier3ToTier2)
+						idxB := borderLeafNode.GetUplinkIndex(u + 1 + ((tier2NodeIndex - 1) * mergedTemplate.MaxUplinksTier3ToTier2))
+						members = append(members, f.alloc.allocateLink(tier2Node, idxA, borderLeafNode, idxB, u+1))
 					}
+					f.addUplinks(topov1alpha1.PositionBorderLeaf, borderLeafNode, members)
+				}
+			}
+		} else {
+			if !mergedTemplate.HasTier1() {
+				return nil, fmt.Errorf("borderLeaf requires a tier1 or a borderLeafPod to connect to")
+			}
+			// the borderleafs are indexed on the superspine after the spines of the pods
+			maxPodIndex := f.getMaxPodIndex()
+			borderLeafIndexes := make([]uint32, len(f.borderLeafNodes))
+			for m, borderLeafNode := range f.borderLeafNodes {
+				borderLeafIndexes[m] = f.alloc.allocateBorderLeafIndex(borderLeafNode.GetNodeName(),
+					0, maxPodIndex*planeSlots+uint32(m)+1)
+			}
+			for n, tier1Node := range f.tier1Nodes {
+				tier1NodeIndex := uint32(n) + 1
+				for m, borderLeafNode := range f.borderLeafNodes {
+					borderLeafIndex := borderLeafIndexes[m]
+
+					uplinksPerNode := borderLeafNode.GetUplinkPerNode()
+					if uplinksPerNode > mergedTemplate.MaxUplinksTier2ToTier1 {
+						return nil, fmt.Errorf("uplink per node %d can not be bigger than maxUplinksTier2ToTier1 %d",
+							uplinksPerNode, mergedTemplate.MaxUplinksTier2ToTier1)
+					}
+
+					// same allocation as the superspine-spine links
+					// superspine Index -> actualUplinkId + (actual borderleafs * max uplinks)
+					// borderleaf Index -> actualUplinkId + (actual superspines * max uplinks)
+					members := make([]FabricLink, 0, uplinksPerNode)
+					for u := uint32(0); u < uplinksPerNode; u++ {
+						idxA := u + 1 + ((borderLeafIndex - 1) * mergedTemplate.MaxUplinksTier2ToTier1)
+						idxB := borderLeafNode.GetUplinkIndex(u + 1 + ((tier1NodeIndex - 1) * mergedTemplate.MaxUplinksTier2ToTier1))
+						members = append(members, f.alloc.allocateLink(tier1Node, idxA, borderLeafNode, idxB, u+1))
+					}
+					f.addUplinks(topov1alpha1.PositionBorderLeaf, borderLeafNode, members)
 				}
 			}
 		}
 	}
+
+	// the highest interface index of every node needs to fit in the ports of the platform
+	if err := f.validatePorts(); err != nil {
+		return nil, err
+	}
 	return f, nil
 }
 
 // +k8s:deepcopy-gen=false
 type fabric struct {
+	name            string
 	log             logging.Logger
 	client          client.Client
+	profiles        InterfaceProfiles
 	m               sync.Mutex
 	tier1Nodes      []FabricNode
+	borderLeafNodes []FabricNode
 	pods            map[uint32]*podInfo
 	tier2tier3Links []FabricLink
 	tier1tier2Links []FabricLink
+	borderLeafLinks []FabricLink
+	// identifies the pod templates of the merged template across renders
+	podKeys []string
+	alloc   *allocator
 }
 
 type podInfo struct {
@@ -221,7 +379,7 @@ func (f *fabric) addNode(pos topov1alpha1.Position, n FabricNode, podIndex uint3
 	defer f.m.Unlock()
 
 	// initialize the tier3/tier3 node struct per podIndex
-	if pos != topov1alpha1.PositionSuperspine {
+	if pos == topov1alpha1.PositionLeaf || pos == topov1alpha1.PositionSpine {
 		if _, ok := f.pods[podIndex]; !ok {
 			f.pods[podIndex] = &podInfo{
 				tier2Nodes: make([]FabricNode, 0),
@@ -237,6 +395,8 @@ func (f *fabric) addNode(pos topov1alpha1.Position, n FabricNode, podIndex uint3
 		f.pods[podIndex].tier2Nodes = append(f.pods[podIndex].tier2Nodes, n)
 	case topov1alpha1.PositionSuperspine:
 		f.tier1Nodes = append(f.tier1Nodes, n)
+	case topov1alpha1.PositionBorderLeaf:
+		f.borderLeafNodes = append(f.borderLeafNodes, n)
 	}
 }
 
@@ -251,12 +411,60 @@ func (f *fabric) getSuperSPines() uint32 {
 	return superspines
 }
 
+// getPlaneSlots identifies the max number of spines of a pod connecting to the same plane
+func (f *fabric) getPlaneSlots(planes *planeModel) uint32 {
+	var planeSlots uint32
+	for p, podInfo := range f.pods {
+		for plane := uint32(1); plane <= planes.planes; plane++ {
+			if n := uint32(len(planes.getPlaneSpines(p, uint32(len(podInfo.tier2Nodes)), plane))); planeSlots < n {
+				planeSlots = n
+			}
+		}
+	}
+	return planeSlots
+}
+
+// getPodIndexes returns the sorted pod indexes such that the fabric is processed
+// in the same order for every render
+func (f *fabric) getPodIndexes() []uint32 {
+	podIndexes := make([]uint32, 0, len(f.pods))
+	for podIndex := range f.pods {
+		podIndexes = append(podIndexes, podIndex)
+	}
+	sort.Slice(podIndexes, func(i, j int) bool { return podIndexes[i] < podIndexes[j] })
+	return podIndexes
+}
+
+// getMaxPodIndex identifies the highest pod index in the fabric
+func (f *fabric) getMaxPodIndex() uint32 {
+	var maxPodIndex uint32
+	for podIndex := range f.pods {
+		if maxPodIndex < podIndex {
+			maxPodIndex = podIndex
+		}
+	}
+	return maxPodIndex
+}
+
 func (f *fabric) addLink(pos topov1alpha1.Position, l FabricLink) {
 	switch pos {
 	case topov1alpha1.PositionSpine:
 		f.tier2tier3Links = append(f.tier2tier3Links, l)
 	case topov1alpha1.PositionSuperspine:
 		f.tier1tier2Links = append(f.tier1tier2Links, l)
+	case topov1alpha1.PositionBorderLeaf:
+		f.borderLeafLinks = append(f.borderLeafLinks, l)
+	}
+}
+
+// addUplinks adds the uplinks of a node to a node of the next tier, the uplinks are
+// bundled in a logical lag link when the node has lag enabled and more than 1 uplink
+func (f *fabric) addUplinks(pos topov1alpha1.Position, n FabricNode, members []FabricLink) {
+	if n.GetLag() && len(members) > 1 {
+		f.addLink(pos, newLagFabricLink(members))
+	}
+	for _, l := range members {
+		f.addLink(pos, l)
 	}
 }
 
@@ -265,6 +473,7 @@ func (f *fabric) processPodNodeTier(tier string, podIndex uint32, tierTempl *top
 	for n := uint32(0); n < tierTempl.NodeNumber; n++ {
 		// n is the node Index within the tier
 		vendorIdx := n % uint32(vendorNum)
+		vendorInfo := tierTempl.VendorInfo[vendorIdx]
 
 		var fabricNode FabricNode
 
@@ -272,14 +481,14 @@ func (f *fabric) processPodNodeTier(tier string, podIndex uint32, tierTempl *top
 			// create a leaf node in the fabric
 			// podIndex is the index of the pod -> counting starts from 1
 			// nodeIndex (n+1) is the nodeIndex within the pod -> countng starts from 1
-			fabricNode = NewLeafFabricNode(podIndex, n+1, tierTempl.UplinksPerNode, tierTempl.VendorInfo[vendorIdx], f.log)
+			fabricNode = NewLeafFabricNode(podIndex, n+1, tierTempl.UplinksPerNode, tierTempl.Lag, vendorInfo, f.getInterfaceProfile(vendorInfo), f.log)
 			f.addNode(topov1alpha1.PositionLeaf, fabricNode, podIndex)
 
 		} else {
 			// create a spine node in the fabric
 			// podIndex is the index of the pod -> counting starts from 1
 			// nodeIndex (n+1) is the nodeIndex within the pod -> countng starts from 1
-			fabricNode = NewSpineFabricNode(podIndex, n+1, tierTempl.UplinksPerNode, tierTempl.VendorInfo[vendorIdx], f.log)
+			fabricNode = NewSpineFabricNode(podIndex, n+1, tierTempl.UplinksPerNode, tierTempl.Lag, vendorInfo, f.getInterfaceProfile(vendorInfo), f.log)
 			f.addNode(topov1alpha1.PositionSpine, fabricNode, podIndex)
 
 		}
@@ -294,13 +503,80 @@ func (f *fabric) SetClient(c client.Client) {
 	f.client = c
 }
 
+func (f *fabric) SetAllocations(a *topov1alpha1.FabricAllocations) {
+	f.alloc = newAllocator(a)
+}
+
+func (f *fabric) GetAllocations() *topov1alpha1.FabricAllocations {
+	return f.alloc.getAllocations()
+}
+
+func (f *fabric) SetInterfaceProfiles(p InterfaceProfiles) {
+	f.profiles = p
+}
+
+// getInterfaceProfile returns the interface profile of the vendorType/platform
+// or nil when no profile is registered, nodes without profile are rejected by
+// validateInterfaceProfiles
+func (f *fabric) getInterfaceProfile(vendorInfo *topov1alpha1.FabricTierVendorInfo) *topov1alpha1.InterfaceProfileProperties {
+	p, ok := f.profiles.GetProfile(vendorInfo.VendorType, vendorInfo.Platform)
+	if !ok {
+		return nil
+	}
+	return p
+}
+
+// validateInterfaceProfiles validates every node has an interface profile
+// with a known port capacity
+func (f *fabric) validateInterfaceProfiles() error {
+	for _, node := range f.GetFabricNodes() {
+		p := node.GetInterfaceProfile()
+		if p == nil {
+			return &InterfaceProfileError{
+				NodeName:   node.GetNodeName(),
+				VendorType: node.GetVendorType(),
+				Platform:   node.GetPlatform(),
+				Reason:     "no interface profile defined",
+			}
+		}
+		if p.Ports == 0 {
+			return &InterfaceProfileError{
+				NodeName:   node.GetNodeName(),
+				VendorType: node.GetVendorType(),
+				Platform:   node.GetPlatform(),
+				Reason:     "interface profile has no port capacity defined",
+			}
+		}
+	}
+	return nil
+}
+
+// validatePorts validates the interfaces of every node fit within the
+// physical ports of the platform
+func (f *fabric) validatePorts() error {
+	for _, node := range f.GetFabricNodes() {
+		if node.GetMaxPort() > node.GetInterfaceProfile().Ports {
+			return &InterfaceProfileError{
+				NodeName:   node.GetNodeName(),
+				VendorType: node.GetVendorType(),
+				Platform:   node.GetPlatform(),
+				Reason: fmt.Sprintf("port %d exceeds the %d ports of the platform",
+					node.GetMaxPort(), node.GetInterfaceProfile().Ports),
+			}
+		}
+	}
+	return nil
+}
+
 func (f *fabric) GetFabricNodes() []FabricNode {
 	fn := make([]FabricNode, 0)
 	fn = append(fn, f.tier1Nodes...)
+	fn = append(fn, f.borderLeafNodes...)
 
 	f.log.Debug("tier2Nodes", "length", len(f.pods))
 
-	for _, podInfo := range f.pods {
+	for _, podIndex := range f.getPodIndexes() {
+		podInfo := f.pods[podIndex]
 		fn = append(fn, podInfo.tier2Nodes...)
 		fn = append(fn, podInfo.tier3Nodes...)
 	}
@@ -308,9 +584,10 @@ func (f *fabric) GetFabricNodes() []FabricNode {
 }
 
 func (f *fabric) GetFabricLinks() []FabricLink {
-	fl := make([]FabricLink, 0, len(f.tier1tier2Links)+len(f.tier2tier3Links))
+	fl := make([]FabricLink, 0, len(f.tier1tier2Links)+len(f.tier2tier3Links)+len(f.borderLeafLinks))
 	fl = append(fl, f.tier1tier2Links...)
 	fl = append(fl, f.tier2tier3Links...)
+	fl = append(fl, f.borderLeafLinks...)
 	return fl
 }
 
@@ -325,7 +602,17 @@ func (f *fabric) PrintNodes() {
 		)
 	}
 
-	for _, podInfo := range f.pods {
+	for _, node := range f.borderLeafNodes {
+		f.log.Debug("borderleaf node",
+			"nodeName", node.GetNodeName(),
+			"vendorType", node.GetVendorType(),
+			"platform", node.GetPlatform(),
+			"position", node.GetPosition(),
+		)
+	}
+
+	for _, podIndex := range f.getPodIndexes() {
+		podInfo := f.pods[podIndex]
 		for _, node := range podInfo.tier2Nodes {
 			f.log.Debug("tier2 node",
 				"nodeName", node.GetNodeName(),
@@ -368,6 +655,15 @@ func (f *fabric) PrintLinks() {
 			"ep B ifName", link.GetEndpointB().IfName,
 		)
 	}
+	for _, link := range f.borderLeafLinks {
+		f.log.Debug("link borderleaf",
+			"ep A nodeName", link.GetEndpointA().Node.GetNodeName(),
+			"ep A podIndex", link.GetEndpointA().Node.GetPodIndex(),
+			"ep A ifName", link.GetEndpointA().IfName,
+			"ep B nodeName", link.GetEndpointB().Node.GetNodeName(),
+			"ep B ifName", link.GetEndpointB().IfName,
+		)
+	}
 }
 
 func (f *fabric) parseTemplate(template *topov1alpha1.FabricTemplate) (*topov1alpha1.FabricTemplate, error) {
@@ -380,19 +676,35 @@ func (f *fabric) parseTemplate(template *topov1alpha1.FabricTemplate) (*topov1al
 	if template.HasReference() {
 		f.log.Debug("parseTemplate", "hasReference", true)
 		mergedTemplate.BorderLeaf = template.BorderLeaf
+		mergedTemplate.BorderLeafPod = template.BorderLeafPod
+		mergedTemplate.Planes = template.Planes
 		mergedTemplate.Tier1 = template.Tier1
 		mergedTemplate.MaxUplinksTier2ToTier1 = template.MaxUplinksTier2ToTier1
-		mergedTemplate.MaxUplinksTier3ToTier2 = template.MaxUplinksTier2ToTier1
+		mergedTemplate.MaxUplinksTier3ToTier2 = template.MaxUplinksTier3ToTier2
 		mergedTemplate.Pod = make([]*topov1alpha1.PodTemplate, 0)
-		for _, pod := range template.Pod {
+		occurrences := map[string]int{}
+		for p, pod := range template.Pod {
+			if !pod.HasReference() {
+				mergedTemplate.Pod = append(mergedTemplate.Pod, pod)
+				f.podKeys = append(f.podKeys, pod.GetKey(p))
+			}
 			if pod.TemplateReference != nil {
+				ref := "template:" + *pod.TemplateReference
+				occurrences[ref]++
+				f.podKeys = append(f.podKeys, fmt.Sprintf("%s#%d", ref, occurrences[ref]))
 				pd, err := f.getPodDefintionFromTemplate(*pod.TemplateReference)
 				if err != nil {
 					return nil, err
 				}
+				// the pod index is defined by the referring template
+				pd = pd.DeepCopy()
+				pd.PodIndex = pod.PodIndex
 				mergedTemplate.Pod = append(mergedTemplate.Pod, pd)
 			}
 			if pod.DefinitionReference != nil {
+				ref := "definition:" + *pod.DefinitionReference
+				occurrences[ref]++
+				f.podKeys = append(f.podKeys, fmt.Sprintf("%s#%d", ref, occurrences[ref]))
 				name, namespace := meta.NamespacedName(*pod.DefinitionReference).GetNameAndNamespace()
 				t := &topov1alpha1.Definition{}
 				if err := f.client.Get(context.TODO(), types.NamespacedName{
@@ -409,12 +721,18 @@ func (f *fabric) parseTemplate(template *topov1alpha1.FabricTemplate) (*topov1al
 				if err != nil {
 					return nil, err
 				}
+				// the pod index is defined by the referring template
+				pd = pd.DeepCopy()
+				pd.PodIndex = pod.PodIndex
 				mergedTemplate.Pod = append(mergedTemplate.Pod, pd)
 
 			}
 		}
 	} else {
 		mergedTemplate = template
+		for p, pod := range template.Pod {
+			f.podKeys = append(f.podKeys, pod.GetKey(p))
+		}
 	}
 
 	return mergedTemplate, nil
@@ -20,6 +20,8 @@ package fabric
 import (
 	"fmt"
 	"strings"
+
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
 )
 
 // +k8s:deepcopy-gen=false
@@ -27,6 +29,8 @@ type FabricLink interface {
 	GetName() string
 	GetEndpointA() *Endpoint
 	GetEndpointB() *Endpoint
+	GetLag() bool
+	GetLagMember() bool
 }
 
 func NewFabricLink(epA *Endpoint, epB *Endpoint) FabricLink {
@@ -39,11 +43,39 @@ func NewFabricLink(epA *Endpoint, epB *Endpoint) FabricLink {
 	}
 }
 
+// newLagFabricLink bundles the member links between 2 nodes in a logical lag link.
+// The lag of a node is named after the interface index of the first member on the node.
+func newLagFabricLink(members []FabricLink) FabricLink {
+	first := members[0]
+	lagNameA := fmt.Sprintf("lag%d", first.GetEndpointA().Index)
+	lagNameB := fmt.Sprintf("lag%d", first.GetEndpointB().Index)
+	for _, m := range members {
+		if l, ok := m.(*fabricLink); ok {
+			l.lagMember = true
+			l.epA.LagName = lagNameA
+			l.epB.LagName = lagNameB
+		}
+	}
+
+	epA := &Endpoint{Node: first.GetEndpointA().Node, IfName: lagNameA, LagName: lagNameA}
+	epB := &Endpoint{Node: first.GetEndpointB().Node, IfName: lagNameB, LagName: lagNameB}
+	return &fabricLink{
+		name: topov1alpha1.LogicalSingleHomedLinkName(epA.Node.GetNodeName(), lagNameA, epB.Node.GetNodeName(), lagNameB),
+		epA:  epA,
+		epB:  epB,
+		lag:  true,
+	}
+}
+
 // +k8s:deepcopy-gen=false
 type fabricLink struct {
 	name string
 	epA  *Endpoint
 	epB  *Endpoint
+	// logical link of a lag
+	lag bool
+	// member link of a lag
+	lagMember bool
 }
 
 func (n *fabricLink) AddInterfaceName(idx uint32) {
@@ -53,6 +85,10 @@ func (n *fabricLink) AddInterfaceName(idx uint32) {
 type Endpoint struct {
 	Node   FabricNode
 	IfName string
+	// interface index of the endpoint
+	Index uint32
+	// name of the lag the endpoint belongs to
+	LagName string
 }
 
 func (l *fabricLink) GetName() string {
@@ -66,3 +102,11 @@ func (l *fabricLink) GetEndpointA() *Endpoint {
 func (l *fabricLink) GetEndpointB() *Endpoint {
 	return l.epB
 }
+
+func (l *fabricLink) GetLag() bool {
+	return l.lag
+}
+
+func (l *fabricLink) GetLagMember() bool {
+	return l.lagMember
+}
@@ -19,6 +19,7 @@ package fabric
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	targetv1 "github.com/yndd/target/apis/target/v1"
@@ -33,40 +34,61 @@ type FabricNode interface {
 	GetNodePlaneIndex() uint32
 	GetPodIndex() uint32
 	GetInterfaceName(idx uint32) string
-	GetInterfaceNameWithPlatfromOffset(idx uint32) string
+	GetUplinkIndex(idx uint32) uint32
 	GetVendorType() targetv1.VendorType
 	GetPlatform() string
 	GetUplinkPerNode() uint32
+	GetLag() bool
+	GetInterfaceProfile() *topov1alpha1.InterfaceProfileProperties
+	GetMaxPort() uint32
+	GetLabels() map[string]string
 }
 
-func NewLeafFabricNode(podIndex, nodeIndex, uplinkPerNode uint32, vendorInfo *topov1alpha1.FabricTierVendorInfo, log logging.Logger) FabricNode {
+func NewLeafFabricNode(podIndex, nodeIndex, uplinkPerNode uint32, lag bool, vendorInfo *topov1alpha1.FabricTierVendorInfo, profile *topov1alpha1.InterfaceProfileProperties, log logging.Logger) FabricNode {
 	return &fabricNode{
 		log:           log,
 		position:      topov1alpha1.PositionLeaf,
 		podIndex:      podIndex,
 		nodeIndex:     nodeIndex,
 		vendorInfo:    vendorInfo,
+		profile:       profile,
 		uplinkPerNode: uplinkPerNode,
+		lag:           lag,
 	}
 }
 
-func NewSpineFabricNode(podIndex, nodeIndex, uplinkPerNode uint32, vendorInfo *topov1alpha1.FabricTierVendorInfo, log logging.Logger) FabricNode {
+func NewSpineFabricNode(podIndex, nodeIndex, uplinkPerNode uint32, lag bool, vendorInfo *topov1alpha1.FabricTierVendorInfo, profile *topov1alpha1.InterfaceProfileProperties, log logging.Logger) FabricNode {
 	return &fabricNode{
 		log:           log,
 		position:      topov1alpha1.PositionSpine,
 		podIndex:      podIndex,
 		nodeIndex:     nodeIndex,
 		vendorInfo:    vendorInfo,
+		profile:       profile,
 		uplinkPerNode: uplinkPerNode,
+		lag:           lag,
 	}
 }
 
-func NewSuperspineFabricNode(nodePlaneIndex, plane uint32, vendorInfo *topov1alpha1.FabricTierVendorInfo, log logging.Logger) FabricNode {
+func NewBorderLeafFabricNode(nodeIndex, uplinkPerNode uint32, lag bool, vendorInfo *topov1alpha1.FabricTierVendorInfo, profile *topov1alpha1.InterfaceProfileProperties, log logging.Logger) FabricNode {
+	return &fabricNode{
+		log:           log,
+		position:      topov1alpha1.PositionBorderLeaf,
+		nodeIndex:     nodeIndex,
+		vendorInfo:    vendorInfo,
+		profile:       profile,
+		uplinkPerNode: uplinkPerNode,
+		lag:           lag,
+	}
+}
+
+func NewSuperspineFabricNode(nodePlaneIndex, plane uint32, vendorInfo *topov1alpha1.FabricTierVendorInfo, profile *topov1alpha1.InterfaceProfileProperties, log logging.Logger) FabricNode {
 	return &fabricNode{
 		log:            log,
 		position:       topov1alpha1.PositionSuperspine,
 		nodeIndex:      plane,
 		vendorInfo:     vendorInfo,
+		profile:        profile,
 		nodePlaneIndex: nodePlaneIndex,
 	}
 }
@@ -77,6 +99,7 @@ type fabricNode struct {
 	position topov1alpha1.Position
 	// for superspines this is the plane Index
 	// for spines/leafs this is the node index within the pod
+	// for borderleafs this is the node index within the borderleaf tier
 	nodeIndex uint32 // relative number within the position/pod
 	// only used for leafs and spines
 	podIndex uint32
@@ -84,61 +107,29 @@ type fabricNode struct {
 	// only used for superspines
 	nodePlaneIndex uint32
 	vendorInfo     *topov1alpha1.FabricTierVendorInfo
+	profile        *topov1alpha1.InterfaceProfileProperties
 	uplinkPerNode  uint32
+	// the uplinks to a node of the next tier are bundled in a lag
+	lag bool
+	// highest physical port used by the interfaces of the node
+	maxPort uint32
 }
 
 func (n *fabricNode) GetInterfaceName(idx uint32) string {
-	return fmt.Sprintf("int-1/%d", idx)
-}
-
-func (n *fabricNode) GetInterfaceNameWithPlatfromOffset(idx uint32) string {
-	n.log.Debug("GetInterfaceNameWithPlatformOffset",
-		"idx", idx,
-		"nodeName", n.GetNodeName(),
-		"podIndex", n.GetPodIndex(),
-		"vendorType", n.GetVendorType(),
-		"platform", n.GetPlatform(),
-		"position", n.GetPosition(),
-	)
-
-	n.log.Debug("GetInterfaceNameWithPlatformOffset",
-		"vendorType", n.GetVendorType(),
-		"vendorType", targetv1.VendorTypeNokiaSRL,
-	)
-	var actualIndex uint32
-	switch n.GetVendorType() {
-	case targetv1.VendorTypeNokiaSRL:
-		n.log.Debug("GetInterfaceNameWithPlatformOffset", "vendorType", targetv1.VendorTypeNokiaSRL)
-		switch n.GetPosition() {
-		case topov1alpha1.PositionLeaf:
-			n.log.Debug("GetInterfaceNameWithPlatformOffset", "position", targetv1.VendorTypeNokiaSRL)
-			switch n.GetPlatform() {
-			case "IXR-D3":
-				n.log.Debug("GetInterfaceNameWithPlatformOffset", "platform", "IXR-D3")
-				actualIndex = idx + 26
-			case "IXR-D2":
-				n.log.Debug("GetInterfaceNameWithPlatformOffset", "platform", "IXR-D2")
-				actualIndex = idx + 48
-			}
-		case topov1alpha1.PositionSpine:
-			switch n.GetPlatform() {
-			case "IXR-D3":
-				n.log.Debug("GetInterfaceNameWithPlatformOffset", "platform", "IXR-D3")
-				actualIndex = idx + 24
-			}
-		}
-	case targetv1.VendorTypeNokiaSROS:
-		// TODO
+	n.setMaxPort(idx)
+	return n.profile.GetInterfaceName(idx)
+}
+
+// GetUplinkIndex returns the interface index of an uplink including the uplink
+// offset of the platform
+func (n *fabricNode) GetUplinkIndex(idx uint32) uint32 {
+	return idx + n.profile.GetUplinkOffset(n.GetPosition())
+}
+
+func (n *fabricNode) setMaxPort(idx uint32) {
+	if port := n.profile.GetPort(idx); port > n.maxPort {
+		n.maxPort = port
 	}
-	n.log.Debug("GetInterfaceNameWithPlatformOffset",
-		"actualIndex", actualIndex,
-		"nodeName", n.GetNodeName(),
-		"podIndex", n.GetPodIndex(),
-		"vendorType", n.GetVendorType(),
-		"platform", n.GetPlatform(),
-		"position", n.GetPosition(),
-	)
-	return fmt.Sprintf("int-1/%d", actualIndex)
 }
 
 func (n *fabricNode) GetPosition() topov1alpha1.Position {
@@ -166,12 +157,29 @@ func (n *fabricNode) GetPlatform() string {
 }
 
 func (n *fabricNode) GetNodeName() string {
-	if n.GetPosition() != topov1alpha1.PositionSuperspine {
+	switch n.GetPosition() {
+	case topov1alpha1.PositionSuperspine:
+		return fmt.Sprintf("%s%d-%d", n.position, n.nodeIndex, n.nodePlaneIndex)
+	case topov1alpha1.PositionBorderLeaf:
+		return fmt.Sprintf("%s%d", n.position, n.nodeIndex)
+	default:
 		return fmt.Sprintf("pod%d-%s%d", n.podIndex, n.position, n.nodeIndex)
+	}
+}
 
-	} else {
-		return fmt.Sprintf("%s%d-%d", n.position, n.nodeIndex, n.nodePlaneIndex)
+// GetLabels returns the labels which identify the node in the fabric, the pod
+// index is only set for the nodes of a pod
+func (n *fabricNode) GetLabels() map[string]string {
+	labels := map[string]string{
+		topov1alpha1.LabelKeyTopologyPosition:   string(n.GetPosition()),
+		topov1alpha1.LabelKeyTopologyNodeIndex:  strconv.Itoa(int(n.GetNodeIndex())),
+		topov1alpha1.LabelKeyTopologyVendorType: string(n.GetVendorType()),
+		topov1alpha1.LabelKeyTopologyPlatform:   n.GetPlatform(),
+	}
+	if n.GetPosition() == topov1alpha1.PositionLeaf || n.GetPosition() == topov1alpha1.PositionSpine {
+		labels[topov1alpha1.LabelKeyTopologyPodIndex] = strconv.Itoa(int(n.GetPodIndex()))
 	}
+	return labels
 }
 
 func (n *fabricNode) GetUplinkPerNode() uint32 {
@@ -180,3 +188,15 @@ func (n *fabricNode) GetUplinkPerNode() uint32 {
 	}
 	return n.uplinkPerNode
 }
+
+func (n *fabricNode) GetLag() bool {
+	return n.lag
+}
+
+func (n *fabricNode) GetInterfaceProfile() *topov1alpha1.InterfaceProfileProperties {
+	return n.profile
+}
+
+func (n *fabricNode) GetMaxPort() uint32 {
+	return n.maxPort
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultInterfaceProfiles are the built-in interface profiles, they can be
+// overwritten or extended with InterfaceProfile resources
+var defaultInterfaceProfiles = []*topov1alpha1.InterfaceProfileProperties{
+	{
+		VendorType: targetv1.VendorTypeNokiaSRL,
+		Platform:   "IXR-D2",
+		Ports:      56,
+		PositionOffsets: []*topov1alpha1.InterfaceProfilePositionOffset{
+			{Position: topov1alpha1.PositionLeaf, Offset: 48},
+			{Position: topov1alpha1.PositionBorderLeaf, Offset: 48},
+		},
+	},
+	{
+		VendorType: targetv1.VendorTypeNokiaSRL,
+		Platform:   "IXR-D3",
+		Ports:      34,
+		PositionOffsets: []*topov1alpha1.InterfaceProfilePositionOffset{
+			{Position: topov1alpha1.PositionLeaf, Offset: 26},
+			{Position: topov1alpha1.PositionBorderLeaf, Offset: 26},
+			{Position: topov1alpha1.PositionSpine, Offset: 24},
+		},
+	},
+}
+
+// +k8s:deepcopy-gen=false
+type InterfaceProfiles interface {
+	// AddProfile adds or replaces the profile of the vendorType/platform
+	AddProfile(p *topov1alpha1.InterfaceProfileProperties)
+	// GetProfile returns the profile of the vendorType/platform
+	GetProfile(vendorType targetv1.VendorType, platform string) (*topov1alpha1.InterfaceProfileProperties, bool)
+}
+
+// NewInterfaceProfiles returns a registry initialized with the default profiles
+func NewInterfaceProfiles() InterfaceProfiles {
+	p := &interfaceProfiles{
+		profiles: map[interfaceProfileKey]*topov1alpha1.InterfaceProfileProperties{},
+	}
+	for _, dp := range defaultInterfaceProfiles {
+		p.AddProfile(dp)
+	}
+	return p
+}
+
+// GetInterfaceProfiles returns the default interface profiles extended with the
+// InterfaceProfiles defined in the namespace
+func GetInterfaceProfiles(ctx context.Context, c client.Client, namespace string) (InterfaceProfiles, error) {
+	profiles := NewInterfaceProfiles()
+
+	ipl := &topov1alpha1.InterfaceProfileList{}
+	if err := c.List(ctx, ipl, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for _, ip := range ipl.Items {
+		if ip.Spec.Properties == nil {
+			continue
+		}
+		profiles.AddProfile(ip.Spec.Properties)
+	}
+	return profiles, nil
+}
+
+type interfaceProfileKey struct {
+	vendorType targetv1.VendorType
+	platform   string
+}
+
+// +k8s:deepcopy-gen=false
+type interfaceProfiles struct {
+	m        sync.RWMutex
+	profiles map[interfaceProfileKey]*topov1alpha1.InterfaceProfileProperties
+}
+
+func (x *interfaceProfiles) AddProfile(p *topov1alpha1.InterfaceProfileProperties) {
+	x.m.Lock()
+	defer x.m.Unlock()
+	x.profiles[interfaceProfileKey{vendorType: p.VendorType, platform: p.Platform}] = p
+}
+
+func (x *interfaceProfiles) GetProfile(vendorType targetv1.VendorType, platform string) (*topov1alpha1.InterfaceProfileProperties, bool) {
+	x.m.RLock()
+	defer x.m.RUnlock()
+	p, ok := x.profiles[interfaceProfileKey{vendorType: vendorType, platform: platform}]
+	return p, ok
+}
+
+// InterfaceProfileError is returned when the interfaces of a fabric node cannot
+// be mapped on the platform of the node
+type InterfaceProfileError struct {
+	NodeName   string
+	VendorType targetv1.VendorType
+	Platform   string
+	Reason     string
+}
+
+func (e *InterfaceProfileError) Error() string {
+	return fmt.Sprintf("node %s with vendorType %s and platform %s: %s", e.NodeName, e.VendorType, e.Platform, e.Reason)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	"fmt"
+	"sort"
+
+	topov1alpha1 "github.com/yndd/topology/apis/topo/v1alpha1"
+)
+
+// planeModel maps the spines of the pods on the superspine planes
+// +k8s:deepcopy-gen=false
+type planeModel struct {
+	// number of superspine planes
+	planes uint32
+	// number of superspines per plane
+	superspinesPerPlane uint32
+	fullMesh            bool
+	// explicit assignments per pod and spine index, pod 0 applies to all pods
+	assignments map[spineKey][]uint32
+}
+
+type spineKey struct {
+	podIndex   uint32
+	spineIndex uint32
+}
+
+// newPlaneModel returns the plane model of the template. Without a plane template
+// every spine index defines a plane with tier1.num superspines, maxSpines is the
+// max number of spines in a pod.
+func newPlaneModel(template *topov1alpha1.FabricTemplate, maxSpines uint32) (*planeModel, error) {
+	pm := &planeModel{
+		planes:              maxSpines,
+		superspinesPerPlane: template.Tier1.NodeNumber,
+		assignments:         map[spineKey][]uint32{},
+	}
+	if template.Planes == nil {
+		return pm, nil
+	}
+
+	pm.planes = template.Planes.PlaneNumber
+	if pm.planes == 0 {
+		return nil, fmt.Errorf("planes requires at least 1 plane")
+	}
+	if template.Planes.SuperspinesPerPlane > 0 {
+		pm.superspinesPerPlane = template.Planes.SuperspinesPerPlane
+	}
+	pm.fullMesh = template.Planes.Mode == topov1alpha1.PlaneModeFullMesh
+	for _, sa := range template.Planes.SpineAssignments {
+		k := spineKey{spineIndex: sa.SpineIndex}
+		if sa.PodIndex != nil {
+			k.podIndex = *sa.PodIndex
+		}
+		planes := make([]uint32, 0, len(sa.Planes))
+		for _, plane := range sa.Planes {
+			if plane == 0 || plane > pm.planes {
+				return nil, fmt.Errorf("spine %d is assigned to plane %d, planes are numbered from 1 to %d", sa.SpineIndex, plane, pm.planes)
+			}
+			planes = append(planes, plane)
+		}
+		sort.Slice(planes, func(i, j int) bool { return planes[i] < planes[j] })
+		pm.assignments[k] = planes
+	}
+	return pm, nil
+}
+
+// getSpinePlanes returns the sorted planes a spine connects to
+func (pm *planeModel) getSpinePlanes(podIndex, spineIndex uint32) []uint32 {
+	if pm.fullMesh {
+		planes := make([]uint32, 0, pm.planes)
+		for p := uint32(1); p <= pm.planes; p++ {
+			planes = append(planes, p)
+		}
+		return planes
+	}
+	if planes, ok := pm.assignments[spineKey{podIndex: podIndex, spineIndex: spineIndex}]; ok {
+		return planes
+	}
+	if planes, ok := pm.assignments[spineKey{spineIndex: spineIndex}]; ok {
+		return planes
+	}
+	return []uint32{((spineIndex - 1) % pm.planes) + 1}
+}
+
+// getPlaneRank returns the position of the plane in the planes the spine connects to,
+// counting from 1, or 0 if the spine does not connect to the plane
+func (pm *planeModel) getPlaneRank(podIndex, spineIndex, plane uint32) uint32 {
+	for i, p := range pm.getSpinePlanes(podIndex, spineIndex) {
+		if p == plane {
+			return uint32(i) + 1
+		}
+	}
+	return 0
+}
+
+// getPlaneSpines returns the spine indexes of the pod connecting to the plane
+func (pm *planeModel) getPlaneSpines(podIndex, spines, plane uint32) []uint32 {
+	planeSpines := []uint32{}
+	for s := uint32(1); s <= spines; s++ {
+		if pm.getPlaneRank(podIndex, s, plane) > 0 {
+			planeSpines = append(planeSpines, s)
+		}
+	}
+	return planeSpines
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fabric
+
+import (
+	"github.com/yndd/ndd-runtime/pkg/meta"
+)
+
+// NamespacedReference returns a template or definition reference as namespace/name,
+// a reference without namespace refers to the namespace of the referring resource
+func NamespacedReference(ref, namespace string) string {
+	name, ns := meta.NamespacedName(ref).GetNameAndNamespace()
+	if name == "" {
+		return meta.GetNamespacedName(namespace, ns)
+	}
+	return meta.GetNamespacedName(ns, name)
+}